@@ -36,6 +36,18 @@ func LoadConfig() *models.Config {
 		stockSymbols = []string{defaultSymbol}
 	}
 
+	// Get additional known symbols from environment
+	knownSymbolsStr := getEnv("KNOWN_SYMBOLS", "")
+	var knownSymbols []string
+	if knownSymbolsStr != "" {
+		for _, symbol := range strings.Split(knownSymbolsStr, ",") {
+			symbol = strings.TrimSpace(symbol)
+			if symbol != "" {
+				knownSymbols = append(knownSymbols, symbol)
+			}
+		}
+	}
+
 	// Get cron schedule times from environment
 	cronScheduleStr := getEnv("CRON_SCHEDULE_TIMES", "")
 	var cronScheduleTimes []string
@@ -49,24 +61,131 @@ func LoadConfig() *models.Config {
 		}
 	}
 
+	// Get ensemble members from environment
+	ensembleStr := getEnv("ENSEMBLE_STRATEGIES", "")
+	var ensembleStrategies []string
+	if ensembleStr != "" {
+		for _, member := range strings.Split(ensembleStr, ",") {
+			member = strings.TrimSpace(member)
+			if member != "" {
+				ensembleStrategies = append(ensembleStrategies, member)
+			}
+		}
+	}
+
+	// Get notification webhook URLs from environment
+	notifyWebhookStr := getEnv("NOTIFY_WEBHOOK_URLS", "")
+	var notifyWebhookURLs []string
+	if notifyWebhookStr != "" {
+		for _, webhookURL := range strings.Split(notifyWebhookStr, ",") {
+			webhookURL = strings.TrimSpace(webhookURL)
+			if webhookURL != "" {
+				notifyWebhookURLs = append(notifyWebhookURLs, webhookURL)
+			}
+		}
+	}
+
 	config := &models.Config{
-		GeminiAPIKey:       getEnv("GEMINI_API_KEY", ""),
-		TelegramBotToken:   getEnv("TELEGRAM_BOT_TOKEN", ""),
-		TelegramChatID:     getEnv("TELEGRAM_CHAT_ID", ""),
-		Port:               getEnv("PORT", "8080"),
-		Environment:        getEnv("ENVIRONMENT", "development"),
-		DefaultStockSymbol: getEnv("DEFAULT_STOCK_SYMBOL", "INDY.JK"),
-		SignalCooldownMins: getEnvAsInt("SIGNAL_COOLDOWN_MINUTES", 5),
-		MinConfidenceLevel: getEnvAsInt("MIN_CONFIDENCE_LEVEL", 70),
-		StockSymbols:       stockSymbols,
-		WebhookURL:         getEnv("WEBHOOK_URL", ""),
-		CronScheduleTimes:  cronScheduleTimes,
-	}
-
-	log.Println(config)
+		GeminiAPIKey:        getEnv("GEMINI_API_KEY", ""),
+		TelegramBotToken:    getEnv("TELEGRAM_BOT_TOKEN", ""),
+		TelegramChatID:      getEnv("TELEGRAM_CHAT_ID", ""),
+		Port:                getEnv("PORT", "8080"),
+		Environment:         getEnv("ENVIRONMENT", "development"),
+		DefaultStockSymbol:  getEnv("DEFAULT_STOCK_SYMBOL", "INDY.JK"),
+		SignalCooldownMins:  getEnvAsInt("SIGNAL_COOLDOWN_MINUTES", 5),
+		MinConfidenceLevel:  getEnvAsInt("MIN_CONFIDENCE_LEVEL", 70),
+		StockSymbols:        stockSymbols,
+		KnownSymbols:        knownSymbols,
+		AllowUnknownSymbols: getEnvAsBool("ALLOW_UNKNOWN_SYMBOLS", false),
+		WebhookURL:          getEnv("WEBHOOK_URL", ""),
+		CronScheduleTimes:   cronScheduleTimes,
+
+		MarketDataProvider:        getEnv("MARKET_DATA_PROVIDER", "yahoo"),
+		MarketDataSymbolProviders: getEnvAsMap("MARKET_DATA_SYMBOL_PROVIDERS"),
+		MarketDataCSVDir:          getEnv("MARKET_DATA_CSV_DIR", ""),
+		MarketDataHTTPURL:         getEnv("MARKET_DATA_HTTP_URL", ""),
+
+		MarketDataTimeoutSecs: getEnvAsInt("MARKET_DATA_TIMEOUT_SECONDS", 30),
+		StrategyTimeoutSecs:   getEnvAsInt("STRATEGY_TIMEOUT_SECONDS", 120),
+
+		RetryMaxAttempts:        getEnvAsInt("RETRY_MAX_ATTEMPTS", 3),
+		RetryBaseDelayMs:        getEnvAsInt("RETRY_BASE_DELAY_MS", 500),
+		RetryMaxDelaySecs:       getEnvAsInt("RETRY_MAX_DELAY_SECONDS", 30),
+		CircuitFailureThreshold: getEnvAsInt("CIRCUIT_BREAKER_THRESHOLD", 5),
+		CircuitCooldownSecs:     getEnvAsInt("CIRCUIT_BREAKER_COOLDOWN_SECONDS", 60),
+
+		BulkConcurrency:      getEnvAsInt("BULK_CONCURRENCY", 4),
+		YahooRequestsPerMin:  getEnvAsInt("YAHOO_REQUESTS_PER_MINUTE", 60),
+		GeminiRequestsPerMin: getEnvAsInt("GEMINI_REQUESTS_PER_MINUTE", 15),
+		GeminiTokensPerMin:   getEnvAsInt("GEMINI_TOKENS_PER_MINUTE", 1000000),
+		BulkMaxRunningJobs:   getEnvAsInt("BULK_MAX_RUNNING_JOBS", 1),
+		BulkMaxJobsPerChat:   getEnvAsInt("BULK_MAX_JOBS_PER_CHAT", 1),
+
+		CandleInterval:        getEnv("CANDLE_INTERVAL", "5m"),
+		CandleRange:           getEnv("CANDLE_RANGE", "2d"),
+		SymbolCandleIntervals: getEnvAsMap("SYMBOL_CANDLE_INTERVALS"),
+		SymbolCandleRanges:    getEnvAsMap("SYMBOL_CANDLE_RANGES"),
+
+		PatternLookback:  getEnvAsInt("PATTERN_LOOKBACK", 10),
+		PatternPrefilter: getEnvAsBool("PATTERN_PREFILTER", false),
+
+		PriceLimitMode: getEnv("PRICE_LIMIT_MODE", "clamp"),
+
+		GeminiLLM: models.LLMSettings{
+			Model:       getEnv("GEMINI_MODEL", "gemini-2.0-flash"),
+			Temperature: getEnvAsFloat("GEMINI_TEMPERATURE", 0.7),
+			TopP:        getEnvAsFloat("GEMINI_TOP_P", 0.8),
+			TopK:        getEnvAsInt("GEMINI_TOP_K", 40),
+		},
+		OpenAIBaseURL: getEnv("OPENAI_BASE_URL", ""),
+		OpenAIAPIKey:  getEnv("OPENAI_API_KEY", ""),
+		OpenAILLM: models.LLMSettings{
+			Model:       getEnv("OPENAI_MODEL", "gpt-4o-mini"),
+			Temperature: getEnvAsFloat("OPENAI_TEMPERATURE", 0.7),
+			TopP:        getEnvAsFloat("OPENAI_TOP_P", 0),
+			TopK:        getEnvAsInt("OPENAI_TOP_K", 0),
+		},
+		OpenAIStructuredOutput: getEnvAsBool("OPENAI_STRUCTURED_OUTPUT", true),
+		LLMRepairAttempts:      getEnvAsInt("LLM_REPAIR_ATTEMPTS", 2),
+
+		Strategy:         getEnv("STRATEGY", "gemini"),
+		SymbolStrategies: getEnvAsMap("SYMBOL_STRATEGIES"),
+		FallbackStrategy: getEnv("FALLBACK_STRATEGY", ""),
+
+		EnsembleStrategies:   ensembleStrategies,
+		EnsembleMethod:       getEnv("ENSEMBLE_METHOD", "majority"),
+		EnsembleMinAgreement: getEnvAsFloat("ENSEMBLE_MIN_AGREEMENT", 60),
+
+		MinRiskRewardRatio: getEnvAsFloat("MIN_RISK_REWARD_RATIO", 2.0),
+
+		SignalHistoryEnabled: getEnvAsBool("SIGNAL_HISTORY_ENABLED", true),
+		SignalStorePath:      getEnv("SIGNAL_STORE_PATH", "data/signals.db"),
+
+		OutcomeTrackingEnabled: getEnvAsBool("OUTCOME_TRACKING_ENABLED", true),
+		OutcomePollMinutes:     getEnvAsInt("OUTCOME_POLL_MINUTES", 15),
+		OutcomeTrackingDays:    getEnvAsInt("OUTCOME_TRACKING_DAYS", 5),
+
+		SymbolMinConfidence: getEnvAsIntMap("SYMBOL_MIN_CONFIDENCE"),
+		SymbolCooldownMins:  getEnvAsIntMap("SYMBOL_SIGNAL_COOLDOWN_MINUTES"),
+		ChatMinConfidence:   getEnvAsIntMap("CHAT_MIN_CONFIDENCE"),
+		ChatCooldownMins:    getEnvAsIntMap("CHAT_SIGNAL_COOLDOWN_MINUTES"),
+
+		NotifyWebhookURLs:           notifyWebhookURLs,
+		NotifyWebhookSecret:         getEnv("NOTIFY_WEBHOOK_SECRET", ""),
+		NotifyWebhookTimeoutSecs:    getEnvAsInt("NOTIFY_WEBHOOK_TIMEOUT_SECONDS", 10),
+		NotifyWebhookDeadLetterPath: getEnv("NOTIFY_WEBHOOK_DEAD_LETTER_PATH", "data/webhook_dead_letters.jsonl"),
+	}
+
+	// A non-positive ratio would accept any BUY/SELL signal; fall back to the default
+	if config.MinRiskRewardRatio <= 0 {
+		log.Printf("MIN_RISK_REWARD_RATIO must be positive, using default: 2.00")
+		config.MinRiskRewardRatio = 2.0
+	}
+
+	log.Println(redactedConfig(config))
 	// Validate required configuration
-	if config.GeminiAPIKey == "" {
-		log.Fatal("GEMINI_API_KEY is required")
+	if config.GeminiAPIKey == "" && usesGemini(config) {
+		log.Fatal("GEMINI_API_KEY is required when the gemini strategy is selected")
 	}
 	if config.TelegramBotToken == "" {
 		log.Fatal("TELEGRAM_BOT_TOKEN is required")
@@ -78,6 +197,35 @@ func LoadConfig() *models.Config {
 	return config
 }
 
+// redactedConfig returns a copy of the configuration with its credentials masked, for logging
+func redactedConfig(config *models.Config) models.Config {
+	redacted := *config
+	for _, secret := range []*string{&redacted.GeminiAPIKey, &redacted.TelegramBotToken, &redacted.OpenAIAPIKey, &redacted.NotifyWebhookSecret} {
+		if *secret != "" {
+			*secret = "[REDACTED]"
+		}
+	}
+	return redacted
+}
+
+// usesGemini reports whether any configured strategy is the Gemini model
+func usesGemini(config *models.Config) bool {
+	if strings.EqualFold(config.Strategy, "gemini") || strings.EqualFold(config.FallbackStrategy, "gemini") {
+		return true
+	}
+	for _, strategy := range config.SymbolStrategies {
+		if strings.EqualFold(strategy, "gemini") {
+			return true
+		}
+	}
+	for _, member := range config.EnsembleStrategies {
+		if name, _, _ := strings.Cut(member, ":"); strings.EqualFold(strings.TrimSpace(name), "gemini") {
+			return true
+		}
+	}
+	return false
+}
+
 // getEnv gets an environment variable with a default value
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -97,3 +245,66 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvAsFloat gets an environment variable as float with a default value
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+		log.Printf("Environment variable %s has invalid float value: %s, using default: %.2f", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+// getEnvAsBool gets an environment variable as boolean with a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+		log.Printf("Environment variable %s has invalid boolean value: %s, using default: %t", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+// getEnvAsMap gets an environment variable as a map from "KEY:value" pairs separated by commas
+// (e.g. "BBCA:csv,TLKM:http"). Keys are upper-cased.
+func getEnvAsMap(key string) map[string]string {
+	result := make(map[string]string)
+	value := os.Getenv(key)
+	if value == "" {
+		return result
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			log.Printf("Environment variable %s has invalid entry: %s, expected KEY:value", key, pair)
+			continue
+		}
+		result[strings.ToUpper(strings.TrimSpace(parts[0]))] = strings.TrimSpace(parts[1])
+	}
+
+	return result
+}
+
+// getEnvAsIntMap gets an environment variable as a map from "KEY:value" pairs with integer values
+// (e.g. "BBCA:80,-1001234567890:60"). Entries with invalid integers are skipped.
+func getEnvAsIntMap(key string) map[string]int {
+	result := make(map[string]int)
+	for name, value := range getEnvAsMap(key) {
+		intValue, err := strconv.Atoi(value)
+		if err != nil {
+			log.Printf("Environment variable %s has invalid integer value for %s: %s", key, name, value)
+			continue
+		}
+		result[name] = intValue
+	}
+	return result
+}
@@ -5,8 +5,10 @@ go 1.21
 require (
 	github.com/gin-gonic/gin v1.9.1
 	github.com/google/generative-ai-go v0.8.0
+	github.com/gorilla/websocket v1.5.3
 	github.com/joho/godotenv v1.4.0
 	github.com/robfig/cron/v3 v3.0.1
+	go.etcd.io/bbolt v1.3.8
 	google.golang.org/api v0.149.0
 )
 
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+	"github.com/farisdewantoro/golang-day-trading-signal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// backtestRequest is the body of a backtest request. The from and to dates accept RFC 3339
+// timestamps or YYYY-MM-DD dates in WIB; a date in to includes the whole day.
+type backtestRequest struct {
+	models.BacktestRequest
+	From string `json:"from" binding:"required"`
+	To   string `json:"to"`
+}
+
+// RunBacktest handles POST requests to replay historical candles through the signal pipeline
+func (h *SignalHandler) RunBacktest(c *gin.Context) {
+	var req backtestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid request format: %v", err),
+		})
+		return
+	}
+
+	request := req.BacktestRequest
+	var err error
+	if request.From, err = parseTimeParam(req.From, false); err == nil {
+		request.To, err = parseTimeParam(req.To, true)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.tradingService.RunBacktest(c.Request.Context(), request)
+	if err != nil {
+		c.JSON(backtestErrorStatus(err), models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("Backtest completed with %d trades", result.Stats.Trades),
+		Data:    result,
+	})
+}
+
+// backtestErrorStatus maps a backtest error to an HTTP status code
+func backtestErrorStatus(err error) int {
+	if errors.Is(err, services.ErrInvalidBacktest) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, services.ErrSignalHistoryDisabled) {
+		return http.StatusServiceUnavailable
+	}
+	return signalErrorStatus(err)
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+	"github.com/farisdewantoro/golang-day-trading-signal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	feedWriteTimeout = 10 * time.Second // Time allowed to write a message to the client
+	feedPongTimeout  = 60 * time.Second // Time allowed between messages or pongs from the client
+	feedPingInterval = 30 * time.Second // How often the client is pinged, within the pong timeout
+)
+
+// feedUpgrader upgrades signal feed requests. Browsers may only connect from the same origin;
+// other clients send no Origin header.
+var feedUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+}
+
+// StreamSignals handles WebSocket connections to the live feed of generated signals. The symbol
+// (comma-separated codes or tickers), signal (comma-separated types), min_confidence and
+// last_seq query parameters set the initial subscription; the client may send a SignalFilter as
+// JSON at any time to replace its filter. Each signal is sent as a "signal" message numbered by
+// seq, and reconnecting with last_seq replays the recent signals that were missed.
+func (h *SignalHandler) StreamSignals(c *gin.Context) {
+	filter, lastSeq, err := bindSignalFilter(c)
+	if err == nil {
+		filter, err = h.tradingService.NormalizeSignalFilter(filter)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	conn, err := feedUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already replied with an error
+		return
+	}
+	defer conn.Close()
+
+	subscription := h.tradingService.SubscribeSignals(filter, lastSeq)
+	defer subscription.Close()
+
+	// The reader applies filter updates and hands the replies to the writer below, which owns
+	// the connection's writes
+	replies := make(chan models.SignalFeedMessage)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	defer close(stopped)
+	go h.readSignalFilters(conn, subscription, replies, done, stopped)
+
+	ping := time.NewTicker(feedPingInterval)
+	defer ping.Stop()
+
+	if writeFeedMessage(conn, models.SignalFeedMessage{Type: services.FeedSubscribed, Filter: &filter, Time: time.Now()}) != nil {
+		return
+	}
+	for {
+		var err error
+		select {
+		case message, ok := <-subscription.Messages():
+			if !ok {
+				// Too far behind or shutting down; the client reconnects with its last seq
+				closing := websocket.FormatCloseMessage(websocket.CloseGoingAway, "reconnect with last_seq to resume")
+				conn.WriteControl(websocket.CloseMessage, closing, time.Now().Add(feedWriteTimeout))
+				return
+			}
+			err = writeFeedMessage(conn, message)
+		case reply := <-replies:
+			err = writeFeedMessage(conn, reply)
+		case <-ping.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(feedWriteTimeout))
+		case <-done:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readSignalFilters reads filter updates from the client until the connection fails, the client
+// stops answering pings or the writer stops, then closes done
+func (h *SignalHandler) readSignalFilters(conn *websocket.Conn, subscription *services.SignalSubscription, replies chan<- models.SignalFeedMessage, done chan<- struct{}, stopped <-chan struct{}) {
+	defer close(done)
+	reply := func(message models.SignalFeedMessage) bool {
+		message.Time = time.Now()
+		select {
+		case replies <- message:
+			return true
+		case <-stopped:
+			return false
+		}
+	}
+
+	conn.SetReadLimit(4096)
+	conn.SetReadDeadline(time.Now().Add(feedPongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(feedPongTimeout))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(feedPongTimeout))
+
+		var filter models.SignalFilter
+		if err := json.Unmarshal(data, &filter); err != nil {
+			if !reply(models.SignalFeedMessage{Type: services.FeedError, Error: fmt.Sprintf("invalid filter: %v", err)}) {
+				return
+			}
+			continue
+		}
+		if filter, err = h.tradingService.NormalizeSignalFilter(filter); err != nil {
+			if !reply(models.SignalFeedMessage{Type: services.FeedError, Error: err.Error()}) {
+				return
+			}
+			continue
+		}
+		subscription.SetFilter(filter)
+		if !reply(models.SignalFeedMessage{Type: services.FeedSubscribed, Filter: &filter}) {
+			return
+		}
+	}
+}
+
+// writeFeedMessage writes a message to the client as JSON
+func writeFeedMessage(conn *websocket.Conn, message models.SignalFeedMessage) error {
+	conn.SetWriteDeadline(time.Now().Add(feedWriteTimeout))
+	return conn.WriteJSON(message)
+}
+
+// bindSignalFilter reads the signal feed filter and resume position from the query string
+func bindSignalFilter(c *gin.Context) (models.SignalFilter, uint64, error) {
+	var filter models.SignalFilter
+	filter.Symbols = splitQueryList(c.Query("symbol"))
+	filter.Signals = splitQueryList(c.Query("signal"))
+
+	if value := c.Query("min_confidence"); value != "" {
+		confidence, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, 0, fmt.Errorf("invalid min_confidence %q", value)
+		}
+		filter.MinConfidence = confidence
+	}
+
+	var lastSeq uint64
+	if value := c.Query("last_seq"); value != "" {
+		var err error
+		if lastSeq, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return filter, 0, fmt.Errorf("invalid last_seq %q", value)
+		}
+	}
+	return filter, lastSeq, nil
+}
+
+// splitQueryList splits a comma-separated query parameter, dropping empty items
+func splitQueryList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
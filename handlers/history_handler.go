@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+	"github.com/farisdewantoro/golang-day-trading-signal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ListSignals handles GET requests for stored signals filtered by symbol, signal type,
+// confidence and date range
+func (h *SignalHandler) ListSignals(c *gin.Context) {
+	query, err := bindSignalQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	records, err := h.tradingService.QuerySignals(query)
+	if err != nil {
+		c.JSON(historyErrorStatus(err), models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("Found %d signals", len(records)),
+		Data:    records,
+	})
+}
+
+// GetStoredSignal handles GET requests for a single stored signal
+func (h *SignalHandler) GetStoredSignal(c *gin.Context) {
+	record, err := h.tradingService.GetSignalRecord(c.Param("id"))
+	if err != nil {
+		c.JSON(historyErrorStatus(err), models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Signal retrieved successfully",
+		Data:    record,
+	})
+}
+
+// ListSummaries handles GET requests for stored bulk analysis summaries in a date range
+func (h *SignalHandler) ListSummaries(c *gin.Context) {
+	query, err := bindSignalQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	records, err := h.tradingService.QuerySummaries(query)
+	if err != nil {
+		c.JSON(historyErrorStatus(err), models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("Found %d summaries", len(records)),
+		Data:    records,
+	})
+}
+
+// GetSignalOutcome handles GET requests for the tracked outcome of a stored signal
+func (h *SignalHandler) GetSignalOutcome(c *gin.Context) {
+	outcome, err := h.tradingService.GetSignalOutcome(c.Param("id"))
+	if err != nil {
+		c.JSON(historyErrorStatus(err), models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Signal outcome retrieved successfully",
+		Data:    outcome,
+	})
+}
+
+// GetOutcomeStats handles GET requests for aggregate hit rates of tracked signals
+func (h *SignalHandler) GetOutcomeStats(c *gin.Context) {
+	query, err := bindSignalQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	stats, err := h.tradingService.GetOutcomeStats(query)
+	if err != nil {
+		c.JSON(historyErrorStatus(err), models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Signal outcome statistics retrieved successfully",
+		Data:    stats,
+	})
+}
+
+// bindSignalQuery reads the history filters from the query string. The from and to
+// parameters accept RFC 3339 timestamps or YYYY-MM-DD dates in WIB; a date in to
+// includes the whole day.
+func bindSignalQuery(c *gin.Context) (models.SignalQuery, error) {
+	var query models.SignalQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		return query, fmt.Errorf("invalid query parameters: %w", err)
+	}
+
+	var err error
+	if query.From, err = parseTimeParam(c.Query("from"), false); err != nil {
+		return query, err
+	}
+	if query.To, err = parseTimeParam(c.Query("to"), true); err != nil {
+		return query, err
+	}
+	return query, nil
+}
+
+// parseTimeParam parses an RFC 3339 timestamp or a YYYY-MM-DD date in WIB.
+// With endOfDay set, a date is moved to the last instant of that day.
+func parseTimeParam(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	location, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		location = time.UTC
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected RFC 3339 or YYYY-MM-DD", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
+
+// historyErrorStatus maps a signal history error to an HTTP status code
+func historyErrorStatus(err error) int {
+	if errors.Is(err, services.ErrRecordNotFound) {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, services.ErrSignalHistoryDisabled) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
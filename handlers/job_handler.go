@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+	"github.com/farisdewantoro/golang-day-trading-signal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ListJobs handles GET requests for the tracked bulk analysis jobs, newest first
+func (h *SignalHandler) ListJobs(c *gin.Context) {
+	jobs := h.tradingService.ListJobs()
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("Found %d jobs", len(jobs)),
+		Data:    jobs,
+	})
+}
+
+// GetJob handles GET requests for the progress and, once complete, the summary of a bulk analysis job
+func (h *SignalHandler) GetJob(c *gin.Context) {
+	job, err := h.tradingService.GetJob(c.Param("id"))
+	if err != nil {
+		c.JSON(jobErrorStatus(err), models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: job.Message,
+		Data:    job,
+	})
+}
+
+// streamHeartbeatInterval is how often an idle job stream sends a comment to keep proxies from
+// closing the connection
+const streamHeartbeatInterval = 15 * time.Second
+
+// StreamJob handles GET requests for a Server-Sent Events stream of a bulk analysis job. The
+// stream opens with a "job" event holding the job's current state, then sends each job event
+// under its type until the job finishes, the client falls too far behind ("stream_dropped") or
+// the client disconnects.
+func (h *SignalHandler) StreamJob(c *gin.Context) {
+	job, events, unsubscribe, err := h.tradingService.SubscribeJob(c.Param("id"))
+	if err != nil {
+		c.JSON(jobErrorStatus(err), models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("job", job)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			c.SSEvent(event.Type, event)
+		case <-heartbeat.C:
+			io.WriteString(c.Writer, ": heartbeat\n\n")
+		case <-c.Request.Context().Done():
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+// CancelJob handles DELETE requests to cancel a running bulk analysis job
+func (h *SignalHandler) CancelJob(c *gin.Context) {
+	if err := h.tradingService.CancelJob(c.Param("id")); err != nil {
+		c.JSON(jobErrorStatus(err), models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Job cancellation requested",
+		Data: map[string]interface{}{
+			"job_id": c.Param("id"),
+		},
+	})
+}
+
+// jobErrorStatus maps a job error to an HTTP status code
+func jobErrorStatus(err error) int {
+	if errors.Is(err, services.ErrJobNotFound) {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, services.ErrJobFinished) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"html"
 	"net/http"
 	"strings"
 
@@ -45,15 +48,18 @@ func (h *SignalHandler) GenerateSignal(c *gin.Context) {
 	}
 
 	// Generate signal
-	signal, err := h.tradingService.GenerateSignal(req.StockSymbol)
+	signal, err := h.tradingService.GenerateSignal(c.Request.Context(), req.StockSymbol, req.Interval, req.Range)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		c.JSON(signalErrorStatus(err), models.APIResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
 		return
 	}
 
+	// Push to Telegram subject to the confidence and cooldown policy
+	h.tradingService.DispatchSignal(req.ChatID, signal)
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Message: "Trading signal generated successfully",
@@ -69,15 +75,18 @@ func (h *SignalHandler) GetSignal(c *gin.Context) {
 	}
 
 	// Generate signal
-	signal, err := h.tradingService.GenerateSignal(symbol)
+	signal, err := h.tradingService.GenerateSignal(c.Request.Context(), symbol, c.Query("interval"), c.Query("range"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		c.JSON(signalErrorStatus(err), models.APIResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
 		return
 	}
 
+	// Push to Telegram subject to the confidence and cooldown policy
+	h.tradingService.DispatchSignal(c.Query("chat_id"), signal)
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Message: "Trading signal generated successfully",
@@ -85,40 +94,86 @@ func (h *SignalHandler) GetSignal(c *gin.Context) {
 	})
 }
 
-// HealthCheck handles health check requests
+// signalErrorStatus maps a signal generation error to an HTTP status code
+func signalErrorStatus(err error) int {
+	if errors.Is(err, services.ErrInvalidCandleSettings) ||
+		errors.Is(err, services.ErrInvalidSymbol) ||
+		errors.Is(err, services.ErrUnknownSymbol) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, services.ErrSignalRejected) {
+		return http.StatusUnprocessableEntity
+	}
+	var timeout *services.StageTimeoutError
+	if errors.As(err, &timeout) {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// HealthCheck handles health check requests. The service stays up while a dependency's
+// circuit breaker is open, so it reports itself as degraded rather than failing.
 func (h *SignalHandler) HealthCheck(c *gin.Context) {
+	dependencies := h.tradingService.DependencyStatuses()
+	status := "healthy"
+	for _, dependency := range dependencies {
+		if dependency.State != services.CircuitClosed {
+			status = "degraded"
+		}
+	}
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Trading Signal Service is healthy",
+		Message: "Trading Signal Service is " + status,
+		Data: map[string]interface{}{
+			"status":       status,
+			"dependencies": dependencies,
+		},
 	})
 }
 
-// GetSignalAll handles GET requests to generate signals for all configured stocks
+// GetSignalAll handles GET requests to generate signals for all configured stocks. While a
+// bulk analysis is running, the request attaches to it instead of starting another.
 func (h *SignalHandler) GetSignalAll(c *gin.Context) {
 	// Start bulk signal analysis in background
-	h.tradingService.GenerateAllSignals()
+	job, started := h.tradingService.GenerateAllSignals(c.Request.Context(), c.Query("chat_id"))
 
+	message := "Bulk signal analysis started. Signals will be sent to Telegram as they are generated"
+	if !started {
+		message = "Bulk signal analysis " + job.JobID + " is already running. Its summary will be sent to Telegram once complete"
+	}
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Bulk signal analysis started. Signals will be sent to Telegram as they are generated.",
-		Data: map[string]interface{}{
-			"status":  "started",
-			"message": "Analysis is running in background. Check Telegram for results.",
-		},
+		Message: message + "; poll /api/v1/jobs/" + job.JobID + " for progress.",
+		Data:    job,
 	})
 }
 
 // GetSignalAllSummary handles GET requests to generate signals for all configured stocks (summary only)
 func (h *SignalHandler) GetSignalAllSummary(c *gin.Context) {
 	// Start bulk signal analysis in background (summary only)
-	h.tradingService.GenerateAllSignalsSummary()
+	job, started := h.tradingService.GenerateAllSignalsSummary(c.Request.Context(), c.Query("chat_id"))
+
+	message := "Bulk signal analysis started. You will receive a summary on Telegram once complete"
+	if !started {
+		message = "Bulk signal analysis " + job.JobID + " is already running. Its summary will be sent to Telegram once complete"
+	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: message + "; poll /api/v1/jobs/" + job.JobID + " for progress.",
+		Data:    job,
+	})
+}
+
+// CancelBulkRuns handles DELETE requests to cancel every running bulk analysis
+func (h *SignalHandler) CancelBulkRuns(c *gin.Context) {
+	cancelled := h.tradingService.CancelBulkRuns()
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Bulk signal analysis started. You will receive a summary on Telegram once complete.",
+		Message: fmt.Sprintf("Cancelled %d bulk analysis runs", cancelled),
 		Data: map[string]interface{}{
-			"status":  "started",
-			"message": "Analysis is running in background. You will receive a 'request received' message and then a summary on Telegram.",
+			"cancelled": cancelled,
 		},
 	})
 }
@@ -173,9 +228,12 @@ func (h *SignalHandler) TelegramWebhook(c *gin.Context) {
 		}
 
 	case text == "/bulk":
-		// Start bulk analysis
-		go h.tradingService.GenerateAllSignals()
-		err := telegramService.SendMessageToChat(chatID, "🚀 Starting bulk analysis for all configured stocks. You will receive signals as they are generated.")
+		// Start bulk analysis, or join the one already running
+		message := "🚀 Starting bulk analysis for all configured stocks. You will receive signals as they are generated."
+		if job, started := h.tradingService.GenerateAllSignals(c.Request.Context(), chatID); !started {
+			message = attachedJobMessage(job)
+		}
+		err := telegramService.SendMessageToChat(chatID, message)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, models.APIResponse{
 				Success: false,
@@ -185,9 +243,12 @@ func (h *SignalHandler) TelegramWebhook(c *gin.Context) {
 		}
 
 	case text == "/summary":
-		// Start bulk analysis with summary
-		go h.tradingService.GenerateAllSignalsSummary()
-		err := telegramService.SendMessageToChat(chatID, "📊 Starting bulk analysis with summary. You will receive a summary once complete.")
+		// Start bulk analysis with summary, or join the one already running
+		message := "📊 Starting bulk analysis with summary. You will receive a summary once complete."
+		if job, started := h.tradingService.GenerateAllSignalsSummary(c.Request.Context(), chatID); !started {
+			message = attachedJobMessage(job)
+		}
+		err := telegramService.SendMessageToChat(chatID, message)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, models.APIResponse{
 				Success: false,
@@ -196,6 +257,26 @@ func (h *SignalHandler) TelegramWebhook(c *gin.Context) {
 			return
 		}
 
+	case text == "/cancel":
+		// Cancel this chat's bulk analyses; runs shared with other chats carry on without it
+		cancelled, detached := h.tradingService.CancelChatBulkRuns(chatID)
+		message := "ℹ️ No bulk analysis is running for this chat."
+		switch {
+		case cancelled > 0 && detached > 0:
+			message = fmt.Sprintf("🛑 Cancelling %d bulk analysis run(s) and leaving %d shared with other chats.", cancelled, detached)
+		case cancelled > 0:
+			message = fmt.Sprintf("🛑 Cancelling %d bulk analysis run(s).", cancelled)
+		case detached > 0:
+			message = fmt.Sprintf("🛑 Left %d bulk analysis run(s) shared with other chats.", detached)
+		}
+		if err := telegramService.SendMessageToChat(chatID, message); err != nil {
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Error:   "Failed to send cancel message",
+			})
+			return
+		}
+
 	case text == "/stocks":
 		// Show configured stocks list
 		stockSymbols := h.tradingService.GetConfiguredStocks()
@@ -208,9 +289,46 @@ func (h *SignalHandler) TelegramWebhook(c *gin.Context) {
 			return
 		}
 
+	case text == "/stats" || strings.HasPrefix(text, "/stats "):
+		// Show the track record of past signals, optionally for one symbol
+		var query models.SignalQuery
+		label := "All symbols"
+		if fields := strings.Fields(text); len(fields) > 1 {
+			query.Symbol = strings.ToUpper(fields[1])
+			label = query.Symbol
+		}
+
+		var err error
+		stats, statsErr := h.tradingService.GetOutcomeStats(query)
+		if statsErr != nil {
+			err = telegramService.SendMessageToChat(chatID, fmt.Sprintf("❌ Failed to load signal statistics: %s", html.EscapeString(statsErr.Error())))
+		} else {
+			err = telegramService.SendOutcomeStatsMessage(chatID, label, stats)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Error:   "Failed to send stats message",
+			})
+			return
+		}
+
 	case text != "" && !strings.HasPrefix(text, "/"):
-		// Treat as stock symbol
-		go h.handleStockSymbolRequest(chatID, text)
+		// Treat as stock symbol with optional interval and range (e.g. "BBCA 15m 5d")
+		fields := strings.Fields(text)
+		symbol, interval, dataRange := fields[0], "", ""
+		if len(fields) > 1 {
+			interval = fields[1]
+		}
+		if len(fields) > 2 {
+			dataRange = fields[2]
+		}
+		// The analysis outlives the webhook request but stops when the service shuts down
+		ctx, cancel := h.tradingService.Detach(c.Request.Context())
+		go func() {
+			defer cancel()
+			h.handleStockSymbolRequest(ctx, chatID, symbol, interval, dataRange)
+		}()
 
 	default:
 		// Unknown command
@@ -230,18 +348,23 @@ func (h *SignalHandler) TelegramWebhook(c *gin.Context) {
 	})
 }
 
+// attachedJobMessage tells a chat that its bulk request joined a running analysis
+func attachedJobMessage(job *models.BulkSignalResult) string {
+	return fmt.Sprintf("⏳ A bulk analysis is already running (%s, %d/%d stocks analyzed). You will receive its summary once complete.", job.JobID, job.Analyzed, job.Total)
+}
+
 // handleStockSymbolRequest handles individual stock symbol requests
-func (h *SignalHandler) handleStockSymbolRequest(chatID, symbol string) {
+func (h *SignalHandler) handleStockSymbolRequest(ctx context.Context, chatID, symbol, interval, dataRange string) {
 	telegramService := h.tradingService.GetTelegramService()
 
 	// Send processing message
-	processingMsg := fmt.Sprintf("🔍 Analyzing %s... Please wait.", symbol)
+	processingMsg := fmt.Sprintf("🔍 Analyzing %s... Please wait.", html.EscapeString(symbol))
 	telegramService.SendMessageToChat(chatID, processingMsg)
 
 	// Generate signal
-	signal, err := h.tradingService.GenerateSignal(symbol)
+	signal, err := h.tradingService.GenerateSignal(ctx, symbol, interval, dataRange)
 	if err != nil {
-		errorMsg := fmt.Sprintf("❌ Failed to analyze %s: %s", symbol, err.Error())
+		errorMsg := fmt.Sprintf("❌ Failed to analyze %s: %s", html.EscapeString(symbol), html.EscapeString(err.Error()))
 		telegramService.SendMessageToChat(chatID, errorMsg)
 		return
 	}
@@ -249,7 +372,7 @@ func (h *SignalHandler) handleStockSymbolRequest(chatID, symbol string) {
 	// Send signal to user
 	err = telegramService.SendTradingSignalToChat(chatID, signal)
 	if err != nil {
-		errorMsg := fmt.Sprintf("❌ Failed to send signal for %s: %s", symbol, err.Error())
+		errorMsg := fmt.Sprintf("❌ Failed to send signal for %s: %s", html.EscapeString(symbol), html.EscapeString(err.Error()))
 		telegramService.SendMessageToChat(chatID, errorMsg)
 		return
 	}
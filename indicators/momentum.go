@@ -0,0 +1,92 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// MACDResult holds the MACD line, signal line and histogram series
+type MACDResult struct {
+	MACD      []float64
+	Signal    []float64
+	Histogram []float64
+}
+
+// RSI calculates the relative strength index of closing prices using Wilder's smoothing
+func RSI(data []models.OHLCData, period int) []float64 {
+	result := nanSeries(len(data))
+	if period <= 0 || len(data) <= period {
+		return result
+	}
+
+	var gainSum, lossSum float64
+	for i := 1; i <= period; i++ {
+		change := data[i].Close - data[i-1].Close
+		if change > 0 {
+			gainSum += change
+		} else {
+			lossSum -= change
+		}
+	}
+
+	avgGain := gainSum / float64(period)
+	avgLoss := lossSum / float64(period)
+	result[period] = rsiValue(avgGain, avgLoss)
+
+	for i := period + 1; i < len(data); i++ {
+		change := data[i].Close - data[i-1].Close
+		gain, loss := 0.0, 0.0
+		if change > 0 {
+			gain = change
+		} else {
+			loss = -change
+		}
+
+		avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+		avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+		result[i] = rsiValue(avgGain, avgLoss)
+	}
+
+	return result
+}
+
+// rsiValue converts average gain and loss into an RSI value
+func rsiValue(avgGain, avgLoss float64) float64 {
+	if avgLoss == 0 {
+		if avgGain == 0 {
+			return 50
+		}
+		return 100
+	}
+	rs := avgGain / avgLoss
+	return 100 - 100/(1+rs)
+}
+
+// MACD calculates the moving average convergence divergence of closing prices
+func MACD(data []models.OHLCData, fastPeriod, slowPeriod, signalPeriod int) MACDResult {
+	closes := Closes(data)
+	fast := EMA(closes, fastPeriod)
+	slow := EMA(closes, slowPeriod)
+
+	macd := nanSeries(len(data))
+	for i := range closes {
+		if !math.IsNaN(fast[i]) && !math.IsNaN(slow[i]) {
+			macd[i] = fast[i] - slow[i]
+		}
+	}
+
+	signal := emaOfSeries(macd, signalPeriod)
+	histogram := nanSeries(len(data))
+	for i := range macd {
+		if !math.IsNaN(macd[i]) && !math.IsNaN(signal[i]) {
+			histogram[i] = macd[i] - signal[i]
+		}
+	}
+
+	return MACDResult{
+		MACD:      macd,
+		Signal:    signal,
+		Histogram: histogram,
+	}
+}
@@ -0,0 +1,90 @@
+// Package indicators computes technical indicators over OHLC candle data.
+//
+// Series functions return a slice aligned with their input. Entries inside the
+// warm-up period of an indicator are NaN.
+package indicators
+
+import (
+	"math"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// Closes extracts closing prices from candles
+func Closes(data []models.OHLCData) []float64 {
+	closes := make([]float64, len(data))
+	for i, candle := range data {
+		closes[i] = candle.Close
+	}
+	return closes
+}
+
+// SMA calculates the simple moving average of values over the given period
+func SMA(values []float64, period int) []float64 {
+	result := nanSeries(len(values))
+	if period <= 0 || len(values) < period {
+		return result
+	}
+
+	var sum float64
+	for i, value := range values {
+		sum += value
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			result[i] = sum / float64(period)
+		}
+	}
+	return result
+}
+
+// EMA calculates the exponential moving average of values over the given period.
+// The first value is seeded with the SMA of the first period values.
+func EMA(values []float64, period int) []float64 {
+	result := nanSeries(len(values))
+	if period <= 0 || len(values) < period {
+		return result
+	}
+
+	var seed float64
+	for _, value := range values[:period] {
+		seed += value
+	}
+	result[period-1] = seed / float64(period)
+
+	multiplier := 2 / float64(period+1)
+	for i := period; i < len(values); i++ {
+		result[i] = (values[i]-result[i-1])*multiplier + result[i-1]
+	}
+	return result
+}
+
+// emaOfSeries calculates an EMA over a series that may start with NaN values
+func emaOfSeries(values []float64, period int) []float64 {
+	start := 0
+	for start < len(values) && math.IsNaN(values[start]) {
+		start++
+	}
+
+	result := nanSeries(len(values))
+	copy(result[start:], EMA(values[start:], period))
+	return result
+}
+
+// nanSeries returns a slice of the given length filled with NaN
+func nanSeries(length int) []float64 {
+	series := make([]float64, length)
+	for i := range series {
+		series[i] = math.NaN()
+	}
+	return series
+}
+
+// Last returns the last value of a series, or NaN when the series is empty
+func Last(series []float64) float64 {
+	if len(series) == 0 {
+		return math.NaN()
+	}
+	return series[len(series)-1]
+}
@@ -0,0 +1,100 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// Default indicator periods used by Compute
+const (
+	FastEMAPeriod     = 5
+	SlowEMAPeriod     = 20
+	SMAPeriod         = 20
+	RSIPeriod         = 14
+	MACDFastPeriod    = 12
+	MACDSlowPeriod    = 26
+	MACDSignalPeriod  = 9
+	BollingerPeriod   = 20
+	BollingerStdDev   = 2.0
+	ATRPeriod         = 14
+	CrossoverLookback = 3
+	OBVTrendLookback  = 10
+)
+
+// Compute calculates the latest value of every supported indicator
+func Compute(data []models.OHLCData) *models.TechnicalIndicators {
+	closes := Closes(data)
+	fastEMA := EMA(closes, FastEMAPeriod)
+	slowEMA := EMA(closes, SlowEMAPeriod)
+	macd := MACD(data, MACDFastPeriod, MACDSlowPeriod, MACDSignalPeriod)
+	bands := BollingerBands(data, BollingerPeriod, BollingerStdDev)
+	obv := OBV(data)
+
+	return &models.TechnicalIndicators{
+		EMA5:           value(Last(fastEMA)),
+		EMA20:          value(Last(slowEMA)),
+		EMACrossover:   Crossover(fastEMA, slowEMA, CrossoverLookback),
+		SMA20:          value(Last(SMA(closes, SMAPeriod))),
+		RSI14:          value(Last(RSI(data, RSIPeriod))),
+		MACD:           value(Last(macd.MACD)),
+		MACDSignal:     value(Last(macd.Signal)),
+		MACDHistogram:  value(Last(macd.Histogram)),
+		BollingerUpper: value(Last(bands.Upper)),
+		BollingerMid:   value(Last(bands.Middle)),
+		BollingerLower: value(Last(bands.Lower)),
+		ATR14:          value(Last(ATR(data, ATRPeriod))),
+		VWAP:           value(Last(VWAP(data))),
+		OBV:            value(Last(obv)),
+		OBVTrend:       trend(obv, OBVTrendLookback),
+		CandleCount:    len(data),
+	}
+}
+
+// Crossover reports whether the fast series crossed the slow series within the last lookback candles.
+// It returns "bullish" for a cross above, "bearish" for a cross below and "none" otherwise.
+func Crossover(fast, slow []float64, lookback int) string {
+	start := len(fast) - lookback
+	if start < 1 {
+		start = 1
+	}
+
+	result := "none"
+	for i := start; i < len(fast) && i < len(slow); i++ {
+		if math.IsNaN(fast[i-1]) || math.IsNaN(slow[i-1]) || math.IsNaN(fast[i]) || math.IsNaN(slow[i]) {
+			continue
+		}
+		if fast[i-1] <= slow[i-1] && fast[i] > slow[i] {
+			result = "bullish"
+		} else if fast[i-1] >= slow[i-1] && fast[i] < slow[i] {
+			result = "bearish"
+		}
+	}
+	return result
+}
+
+// trend reports whether a series is rising, falling or flat over the last lookback values
+func trend(series []float64, lookback int) string {
+	if len(series) <= lookback {
+		return ""
+	}
+
+	first, last := series[len(series)-1-lookback], Last(series)
+	switch {
+	case last > first:
+		return "rising"
+	case last < first:
+		return "falling"
+	default:
+		return "flat"
+	}
+}
+
+// value returns nil for NaN and infinite values so unavailable indicators are distinguishable
+// from legitimate zeros and snapshots can be encoded as JSON
+func value(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
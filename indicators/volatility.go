@@ -0,0 +1,80 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// BollingerBandsResult holds the upper, middle and lower band series
+type BollingerBandsResult struct {
+	Upper  []float64
+	Middle []float64
+	Lower  []float64
+}
+
+// BollingerBands calculates Bollinger Bands of closing prices with the given period and standard deviation multiplier
+func BollingerBands(data []models.OHLCData, period int, multiplier float64) BollingerBandsResult {
+	closes := Closes(data)
+	middle := SMA(closes, period)
+	upper := nanSeries(len(data))
+	lower := nanSeries(len(data))
+
+	for i := range closes {
+		if math.IsNaN(middle[i]) {
+			continue
+		}
+
+		var variance float64
+		for _, value := range closes[i-period+1 : i+1] {
+			variance += (value - middle[i]) * (value - middle[i])
+		}
+		stdDev := math.Sqrt(variance / float64(period))
+
+		upper[i] = middle[i] + multiplier*stdDev
+		lower[i] = middle[i] - multiplier*stdDev
+	}
+
+	return BollingerBandsResult{
+		Upper:  upper,
+		Middle: middle,
+		Lower:  lower,
+	}
+}
+
+// TrueRange calculates the true range of each candle
+func TrueRange(data []models.OHLCData) []float64 {
+	result := make([]float64, len(data))
+	for i, candle := range data {
+		if i == 0 {
+			result[i] = candle.High - candle.Low
+			continue
+		}
+
+		prevClose := data[i-1].Close
+		result[i] = math.Max(candle.High-candle.Low,
+			math.Max(math.Abs(candle.High-prevClose), math.Abs(candle.Low-prevClose)))
+	}
+	return result
+}
+
+// ATR calculates the average true range using Wilder's smoothing
+func ATR(data []models.OHLCData, period int) []float64 {
+	result := nanSeries(len(data))
+	if period <= 0 || len(data) < period {
+		return result
+	}
+
+	trueRange := TrueRange(data)
+
+	var sum float64
+	for _, value := range trueRange[:period] {
+		sum += value
+	}
+	result[period-1] = sum / float64(period)
+
+	for i := period; i < len(data); i++ {
+		result[i] = (result[i-1]*float64(period-1) + trueRange[i]) / float64(period)
+	}
+	return result
+}
@@ -0,0 +1,45 @@
+package indicators
+
+import (
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// VWAP calculates the volume weighted average price, resetting at the start of each trading day
+func VWAP(data []models.OHLCData) []float64 {
+	result := nanSeries(len(data))
+
+	var cumulativePV, cumulativeVolume float64
+	var currentDay string
+	for i, candle := range data {
+		day := candle.Timestamp.Format("2006-01-02")
+		if day != currentDay {
+			currentDay = day
+			cumulativePV, cumulativeVolume = 0, 0
+		}
+
+		typicalPrice := (candle.High + candle.Low + candle.Close) / 3
+		cumulativePV += typicalPrice * float64(candle.Volume)
+		cumulativeVolume += float64(candle.Volume)
+
+		if cumulativeVolume > 0 {
+			result[i] = cumulativePV / cumulativeVolume
+		}
+	}
+	return result
+}
+
+// OBV calculates the on-balance volume
+func OBV(data []models.OHLCData) []float64 {
+	result := make([]float64, len(data))
+	for i := 1; i < len(data); i++ {
+		switch {
+		case data[i].Close > data[i-1].Close:
+			result[i] = result[i-1] + float64(data[i].Volume)
+		case data[i].Close < data[i-1].Close:
+			result[i] = result[i-1] - float64(data[i].Volume)
+		default:
+			result[i] = result[i-1]
+		}
+	}
+	return result
+}
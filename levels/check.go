@@ -0,0 +1,91 @@
+package levels
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// Check compares a signal's buy price, target and stop loss against the computed levels
+// and returns a warning for each price that contradicts them
+func Check(signal *models.TradingSignal, keyLevels *models.KeyLevels) []string {
+	if signal == nil || keyLevels == nil {
+		return nil
+	}
+
+	var warnings []string
+	switch strings.ToUpper(signal.Signal) {
+	case "BUY":
+		if support := nearestBelow(keyLevels, signal.BuyPrice); support > 0 && signal.StopLoss > support {
+			warnings = append(warnings, fmt.Sprintf("stop loss %.2f is above the nearest support %.2f below the entry", signal.StopLoss, support))
+		}
+		if resistance := nearestAbove(keyLevels, signal.BuyPrice); resistance > 0 && signal.TargetPrice > resistance {
+			warnings = append(warnings, fmt.Sprintf("target %.2f is beyond the nearest resistance %.2f above the entry", signal.TargetPrice, resistance))
+		}
+		if keyLevels.NearestResistance > 0 && signal.BuyPrice > keyLevels.NearestResistance {
+			warnings = append(warnings, fmt.Sprintf("buy price %.2f is above the nearest resistance %.2f and needs a breakout", signal.BuyPrice, keyLevels.NearestResistance))
+		}
+	case "SELL":
+		if resistance := nearestAbove(keyLevels, signal.BuyPrice); resistance > 0 && signal.StopLoss < resistance {
+			warnings = append(warnings, fmt.Sprintf("stop loss %.2f is below the nearest resistance %.2f above the entry", signal.StopLoss, resistance))
+		}
+		if support := nearestBelow(keyLevels, signal.BuyPrice); support > 0 && signal.TargetPrice < support {
+			warnings = append(warnings, fmt.Sprintf("target %.2f is beyond the nearest support %.2f below the entry", signal.TargetPrice, support))
+		}
+		if keyLevels.NearestSupport > 0 && signal.BuyPrice < keyLevels.NearestSupport {
+			warnings = append(warnings, fmt.Sprintf("sell price %.2f is below the nearest support %.2f and needs a breakdown", signal.BuyPrice, keyLevels.NearestSupport))
+		}
+	}
+
+	return warnings
+}
+
+// nearestBelow returns the highest computed level strictly below a price, or zero when there is none
+func nearestBelow(keyLevels *models.KeyLevels, price float64) float64 {
+	var nearest float64
+	for _, level := range allLevels(keyLevels) {
+		if level < price && level > nearest {
+			nearest = level
+		}
+	}
+	return nearest
+}
+
+// nearestAbove returns the lowest computed level strictly above a price, or zero when there is none
+func nearestAbove(keyLevels *models.KeyLevels, price float64) float64 {
+	var nearest float64
+	for _, level := range allLevels(keyLevels) {
+		if level > price && (nearest == 0 || level < nearest) {
+			nearest = level
+		}
+	}
+	return nearest
+}
+
+// allLevels returns the pivot, previous-session, swing and volume levels on both sides of the last close
+func allLevels(keyLevels *models.KeyLevels) []float64 {
+	var prices []float64
+	for _, pivots := range []*models.PivotLevels{keyLevels.Classic, keyLevels.Fibonacci, keyLevels.Camarilla} {
+		if pivots == nil {
+			continue
+		}
+		prices = append(prices, pivots.Pivot, pivots.R1, pivots.R2, pivots.R3, pivots.S1, pivots.S2, pivots.S3)
+		if pivots.R4 > 0 {
+			prices = append(prices, pivots.R4)
+		}
+		if pivots.S4 > 0 {
+			prices = append(prices, pivots.S4)
+		}
+	}
+	if keyLevels.PreviousHigh > 0 {
+		prices = append(prices, keyLevels.PreviousHigh, keyLevels.PreviousLow)
+	}
+	for _, level := range keyLevels.Supports {
+		prices = append(prices, level.Price)
+	}
+	for _, level := range keyLevels.Resistances {
+		prices = append(prices, level.Price)
+	}
+	return prices
+}
@@ -0,0 +1,103 @@
+// Package levels computes support, resistance and pivot levels from OHLC candle data.
+package levels
+
+import (
+	"sort"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// Default parameters used by Compute
+const (
+	SwingWindow       = 2     // Candles on each side that a swing high/low must exceed
+	ClusterTolerance  = 0.005 // Swing points within 0.5% of each other form one level
+	VolumeProfileBins = 24
+	ValueAreaShare    = 0.7
+	HighVolumeFactor  = 1.5 // Bins above 1.5x the average bin volume are high-volume nodes
+	MaxLevels         = 3   // Maximum supports and resistances kept on each side
+)
+
+// Compute calculates pivot levels from the previous session and swing and volume based
+// levels from all candles. Session boundaries are determined in the given location.
+func Compute(data []models.OHLCData, location *time.Location) *models.KeyLevels {
+	if len(data) == 0 {
+		return nil
+	}
+	if location == nil {
+		location = time.UTC
+	}
+
+	result := &models.KeyLevels{}
+
+	if high, low, close, ok := previousSession(data, location); ok {
+		result.PreviousHigh = high
+		result.PreviousLow = low
+		result.PreviousClose = close
+		result.Classic = ClassicPivots(high, low, close)
+		result.Fibonacci = FibonacciPivots(high, low, close)
+		result.Camarilla = CamarillaPivots(high, low, close)
+	}
+
+	lastClose := data[len(data)-1].Close
+	candidates := SwingLevels(data, SwingWindow, ClusterTolerance)
+
+	result.VolumeProfile = BuildVolumeProfile(data, VolumeProfileBins)
+	if result.VolumeProfile != nil {
+		candidates = append(candidates, result.VolumeProfile.Nodes...)
+	}
+
+	for _, level := range candidates {
+		if level.Price < lastClose {
+			result.Supports = append(result.Supports, level)
+		} else if level.Price > lastClose {
+			result.Resistances = append(result.Resistances, level)
+		}
+	}
+
+	// Order both sides nearest first
+	sort.Slice(result.Supports, func(i, j int) bool { return result.Supports[i].Price > result.Supports[j].Price })
+	sort.Slice(result.Resistances, func(i, j int) bool { return result.Resistances[i].Price < result.Resistances[j].Price })
+
+	if len(result.Supports) > MaxLevels {
+		result.Supports = result.Supports[:MaxLevels]
+	}
+	if len(result.Resistances) > MaxLevels {
+		result.Resistances = result.Resistances[:MaxLevels]
+	}
+
+	if len(result.Supports) > 0 {
+		result.NearestSupport = result.Supports[0].Price
+	}
+	if len(result.Resistances) > 0 {
+		result.NearestResistance = result.Resistances[0].Price
+	}
+
+	return result
+}
+
+// previousSession returns the high, low and close of the session before the latest one
+func previousSession(data []models.OHLCData, location *time.Location) (float64, float64, float64, bool) {
+	lastDay := data[len(data)-1].Timestamp.In(location).Format("2006-01-02")
+
+	end := len(data) - 1
+	for end >= 0 && data[end].Timestamp.In(location).Format("2006-01-02") == lastDay {
+		end--
+	}
+	if end < 0 {
+		return 0, 0, 0, false
+	}
+
+	previousDay := data[end].Timestamp.In(location).Format("2006-01-02")
+	high, low, close := data[end].High, data[end].Low, data[end].Close
+	for i := end; i >= 0 && data[i].Timestamp.In(location).Format("2006-01-02") == previousDay; i-- {
+		if data[i].High > high {
+			high = data[i].High
+		}
+		if data[i].Low < low {
+			low = data[i].Low
+		}
+	}
+
+	return high, low, close, true
+}
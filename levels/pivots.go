@@ -0,0 +1,50 @@
+package levels
+
+import (
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// ClassicPivots calculates floor trader pivot levels
+func ClassicPivots(high, low, close float64) *models.PivotLevels {
+	pivot := (high + low + close) / 3
+	return &models.PivotLevels{
+		Pivot: pivot,
+		R1:    2*pivot - low,
+		R2:    pivot + (high - low),
+		R3:    high + 2*(pivot-low),
+		S1:    2*pivot - high,
+		S2:    pivot - (high - low),
+		S3:    low - 2*(high-pivot),
+	}
+}
+
+// FibonacciPivots calculates pivot levels using Fibonacci retracements of the previous range
+func FibonacciPivots(high, low, close float64) *models.PivotLevels {
+	pivot := (high + low + close) / 3
+	priceRange := high - low
+	return &models.PivotLevels{
+		Pivot: pivot,
+		R1:    pivot + 0.382*priceRange,
+		R2:    pivot + 0.618*priceRange,
+		R3:    pivot + priceRange,
+		S1:    pivot - 0.382*priceRange,
+		S2:    pivot - 0.618*priceRange,
+		S3:    pivot - priceRange,
+	}
+}
+
+// CamarillaPivots calculates Camarilla pivot levels
+func CamarillaPivots(high, low, close float64) *models.PivotLevels {
+	priceRange := (high - low) * 1.1
+	return &models.PivotLevels{
+		Pivot: (high + low + close) / 3,
+		R1:    close + priceRange/12,
+		R2:    close + priceRange/6,
+		R3:    close + priceRange/4,
+		R4:    close + priceRange/2,
+		S1:    close - priceRange/12,
+		S2:    close - priceRange/6,
+		S3:    close - priceRange/4,
+		S4:    close - priceRange/2,
+	}
+}
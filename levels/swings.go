@@ -0,0 +1,155 @@
+package levels
+
+import (
+	"math"
+	"sort"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// SwingLevels finds swing highs and lows and clusters nearby swing points into levels.
+// A swing high (low) is a candle whose high (low) is the extreme of the window
+// candles on each side. Each level's strength is the number of swing points in the cluster.
+func SwingLevels(data []models.OHLCData, window int, tolerance float64) []models.PriceLevel {
+	var points []float64
+	for i := window; i < len(data)-window; i++ {
+		isHigh, isLow := true, true
+		for j := i - window; j <= i+window; j++ {
+			if j == i {
+				continue
+			}
+			if data[j].High >= data[i].High {
+				isHigh = false
+			}
+			if data[j].Low <= data[i].Low {
+				isLow = false
+			}
+		}
+		if isHigh {
+			points = append(points, data[i].High)
+		}
+		if isLow {
+			points = append(points, data[i].Low)
+		}
+	}
+
+	if len(points) == 0 {
+		return nil
+	}
+	sort.Float64s(points)
+
+	var levels []models.PriceLevel
+	clusterStart := 0
+	for i := 1; i <= len(points); i++ {
+		if i < len(points) && points[i]-points[clusterStart] <= points[clusterStart]*tolerance {
+			continue
+		}
+
+		var sum float64
+		for _, point := range points[clusterStart:i] {
+			sum += point
+		}
+		levels = append(levels, models.PriceLevel{
+			Price:    sum / float64(i-clusterStart),
+			Source:   "swing",
+			Strength: i - clusterStart,
+		})
+		clusterStart = i
+	}
+
+	return levels
+}
+
+// BuildVolumeProfile distributes each candle's volume across the price bins its range covers
+// and returns the point of control, value area and high-volume nodes
+func BuildVolumeProfile(data []models.OHLCData, bins int) *models.VolumeProfile {
+	if len(data) == 0 || bins <= 0 {
+		return nil
+	}
+
+	low, high := data[0].Low, data[0].High
+	for _, candle := range data {
+		low = math.Min(low, candle.Low)
+		high = math.Max(high, candle.High)
+	}
+	if high <= low {
+		return nil
+	}
+
+	binSize := (high - low) / float64(bins)
+	volumes := make([]float64, bins)
+	var totalVolume float64
+	for _, candle := range data {
+		first := binIndex(candle.Low, low, binSize, bins)
+		last := binIndex(candle.High, low, binSize, bins)
+		share := float64(candle.Volume) / float64(last-first+1)
+		for b := first; b <= last; b++ {
+			volumes[b] += share
+		}
+		totalVolume += float64(candle.Volume)
+	}
+	if totalVolume == 0 {
+		return nil
+	}
+
+	binPrice := func(b int) float64 { return low + (float64(b)+0.5)*binSize }
+
+	pointOfControl := 0
+	for b := range volumes {
+		if volumes[b] > volumes[pointOfControl] {
+			pointOfControl = b
+		}
+	}
+
+	// Expand the value area from the point of control towards the larger neighbour
+	areaLow, areaHigh := pointOfControl, pointOfControl
+	areaVolume := volumes[pointOfControl]
+	for areaVolume < ValueAreaShare*totalVolume && (areaLow > 0 || areaHigh < bins-1) {
+		below, above := -1.0, -1.0
+		if areaLow > 0 {
+			below = volumes[areaLow-1]
+		}
+		if areaHigh < bins-1 {
+			above = volumes[areaHigh+1]
+		}
+		if above >= below {
+			areaHigh++
+			areaVolume += above
+		} else {
+			areaLow--
+			areaVolume += below
+		}
+	}
+
+	profile := &models.VolumeProfile{
+		PointOfControl: binPrice(pointOfControl),
+		ValueAreaHigh:  low + float64(areaHigh+1)*binSize,
+		ValueAreaLow:   low + float64(areaLow)*binSize,
+	}
+
+	average := totalVolume / float64(bins)
+	for b, volume := range volumes {
+		isPeak := (b == 0 || volume >= volumes[b-1]) && (b == bins-1 || volume >= volumes[b+1])
+		if isPeak && volume >= HighVolumeFactor*average {
+			profile.Nodes = append(profile.Nodes, models.PriceLevel{
+				Price:    binPrice(b),
+				Source:   "volume",
+				Strength: int(math.Round(100 * volume / totalVolume)),
+			})
+		}
+	}
+
+	return profile
+}
+
+// binIndex returns the volume profile bin containing a price
+func binIndex(price, low, binSize float64, bins int) int {
+	index := int((price - low) / binSize)
+	if index >= bins {
+		return bins - 1
+	}
+	if index < 0 {
+		return 0
+	}
+	return index
+}
@@ -44,6 +44,19 @@ func main() {
 		log.Println("No cron schedule times configured, skipping cron scheduler")
 	}
 
+	// Start tracking signal outcomes when the signal history is enabled
+	var outcomeTracker *services.OutcomeTracker
+	if cfg.SignalHistoryEnabled && cfg.OutcomeTrackingEnabled {
+		outcomeTracker, err = services.NewOutcomeTracker(tradingService,
+			time.Duration(cfg.OutcomePollMinutes)*time.Minute,
+			time.Duration(cfg.OutcomeTrackingDays)*24*time.Hour)
+		if err != nil {
+			log.Printf("Failed to create outcome tracker: %v", err)
+		} else {
+			outcomeTracker.Start()
+		}
+	}
+
 	// Set Gin mode
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -68,6 +81,18 @@ func main() {
 		api.POST("/signal", signalHandler.GenerateSignal)
 		api.GET("/signal-all", signalHandler.GetSignalAll)
 		api.GET("/signal-all-summary", signalHandler.GetSignalAllSummary)
+		api.DELETE("/signal-all", signalHandler.CancelBulkRuns)
+		api.GET("/jobs", signalHandler.ListJobs)
+		api.GET("/jobs/:id", signalHandler.GetJob)
+		api.GET("/jobs/:id/stream", signalHandler.StreamJob)
+		api.DELETE("/jobs/:id", signalHandler.CancelJob)
+		api.GET("/ws", signalHandler.StreamSignals)
+		api.GET("/signals", signalHandler.ListSignals)
+		api.GET("/signals/:id", signalHandler.GetStoredSignal)
+		api.GET("/signals/:id/outcome", signalHandler.GetSignalOutcome)
+		api.GET("/stats", signalHandler.GetOutcomeStats)
+		api.GET("/summaries", signalHandler.ListSummaries)
+		api.POST("/backtest", signalHandler.RunBacktest)
 		api.GET("/cron-status", signalHandler.GetCronStatus)
 		api.POST("/webhook/setup", signalHandler.SetupWebhook)
 		api.DELETE("/webhook", signalHandler.DeleteWebhook)
@@ -114,6 +139,12 @@ func main() {
 		log.Println("Cron scheduler stopped")
 	}
 
+	// Stop outcome tracker if running
+	if outcomeTracker != nil {
+		outcomeTracker.Stop()
+		log.Println("Outcome tracker stopped")
+	}
+
 	// Give outstanding requests a deadline for completion
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // OHLCData represents a single candlestick data point
 type OHLCData struct {
@@ -22,17 +25,201 @@ type OHLCVAnalysis struct {
 	Explanation string  `json:"explanation"`
 }
 
+// TechnicalIndicators represents the latest values of the computed technical indicators.
+// Values that could not be computed because there were too few candles are nil.
+type TechnicalIndicators struct {
+	EMA5           *float64 `json:"ema_5,omitempty"`
+	EMA20          *float64 `json:"ema_20,omitempty"`
+	EMACrossover   string   `json:"ema_crossover,omitempty"` // "bullish", "bearish" or "none" over the last candles
+	SMA20          *float64 `json:"sma_20,omitempty"`
+	RSI14          *float64 `json:"rsi_14,omitempty"`
+	MACD           *float64 `json:"macd,omitempty"`
+	MACDSignal     *float64 `json:"macd_signal,omitempty"`
+	MACDHistogram  *float64 `json:"macd_histogram,omitempty"`
+	BollingerUpper *float64 `json:"bollinger_upper,omitempty"`
+	BollingerMid   *float64 `json:"bollinger_middle,omitempty"`
+	BollingerLower *float64 `json:"bollinger_lower,omitempty"`
+	ATR14          *float64 `json:"atr_14,omitempty"`
+	VWAP           *float64 `json:"vwap,omitempty"`
+	OBV            *float64 `json:"obv,omitempty"`
+	OBVTrend       string   `json:"obv_trend,omitempty"` // "rising", "falling" or "flat" over the last candles
+	CandleCount    int      `json:"candle_count"`
+}
+
+// CandlestickPattern represents a candlestick pattern detected in the candle data
+type CandlestickPattern struct {
+	Name      string    `json:"name"`      // e.g. "bullish_engulfing", "doji", "hammer"
+	Direction string    `json:"direction"` // "bullish", "bearish" or "neutral"
+	Index     int       `json:"index"`     // Index of the candle that completes the pattern
+	Timestamp time.Time `json:"timestamp"` // Timestamp of the candle that completes the pattern
+	Strength  int       `json:"strength"`  // 0-100
+}
+
+// PivotLevels represents pivot point levels computed from the previous session
+type PivotLevels struct {
+	Pivot float64 `json:"pivot"`
+	R1    float64 `json:"r1"`
+	R2    float64 `json:"r2"`
+	R3    float64 `json:"r3"`
+	R4    float64 `json:"r4,omitempty"`
+	S1    float64 `json:"s1"`
+	S2    float64 `json:"s2"`
+	S3    float64 `json:"s3"`
+	S4    float64 `json:"s4,omitempty"`
+}
+
+// PriceLevel represents a support or resistance level derived from the candles
+type PriceLevel struct {
+	Price    float64 `json:"price"`
+	Source   string  `json:"source"`   // "swing" or "volume"
+	Strength int     `json:"strength"` // Number of swing touches, or volume share in percent
+}
+
+// VolumeProfile represents the traded volume distribution over price
+type VolumeProfile struct {
+	PointOfControl float64      `json:"point_of_control"`
+	ValueAreaHigh  float64      `json:"value_area_high"`
+	ValueAreaLow   float64      `json:"value_area_low"`
+	Nodes          []PriceLevel `json:"nodes,omitempty"` // High-volume nodes
+}
+
+// KeyLevels represents the algorithmically computed support, resistance and pivot levels
+type KeyLevels struct {
+	PreviousHigh      float64        `json:"previous_high,omitempty"`
+	PreviousLow       float64        `json:"previous_low,omitempty"`
+	PreviousClose     float64        `json:"previous_close,omitempty"`
+	Classic           *PivotLevels   `json:"classic,omitempty"`
+	Fibonacci         *PivotLevels   `json:"fibonacci,omitempty"`
+	Camarilla         *PivotLevels   `json:"camarilla,omitempty"`
+	Supports          []PriceLevel   `json:"supports,omitempty"`    // Below the last close, nearest first
+	Resistances       []PriceLevel   `json:"resistances,omitempty"` // Above the last close, nearest first
+	VolumeProfile     *VolumeProfile `json:"volume_profile,omitempty"`
+	NearestSupport    float64        `json:"nearest_support,omitempty"`
+	NearestResistance float64        `json:"nearest_resistance,omitempty"`
+}
+
 // TradingSignal represents the AI-generated trading signal
 type TradingSignal struct {
-	Signal        string         `json:"signal"` // "BUY", "SELL", or "WAIT"
+	ID            string         `json:"id,omitempty"` // Signal history ID, when the signal was stored
+	Signal        string         `json:"signal"`       // "BUY", "SELL", or "WAIT"
 	BuyPrice      float64        `json:"buy_price"`
 	TargetPrice   float64        `json:"target_price"`
 	StopLoss      float64        `json:"stop_loss"`
 	Confidence    int            `json:"confidence"` // 0-100
 	Reason        string         `json:"reason"`
 	StockSymbol   string         `json:"stock_symbol"`
+	Exchange      string         `json:"exchange,omitempty"` // Exchange code (e.g. "IDX")
+	Interval      string         `json:"interval,omitempty"` // Candle interval used for the analysis
+	Range         string         `json:"range,omitempty"`    // Candle lookback range used for the analysis
 	GeneratedAt   time.Time      `json:"generated_at"`
 	OHLCVAnalysis *OHLCVAnalysis `json:"ohlcv_analysis,omitempty"`
+	Model         string         `json:"model,omitempty"`       // Model that generated the signal
+	PromptHash    string         `json:"prompt_hash,omitempty"` // SHA-256 of the prompt sent to the model
+
+	Indicators *TechnicalIndicators `json:"indicators,omitempty"` // Deterministically computed indicators fed to the model
+	Patterns   []CandlestickPattern `json:"patterns,omitempty"`   // Candlestick patterns detected in the recent candles
+	Levels     *KeyLevels           `json:"levels,omitempty"`     // Computed support, resistance and pivot levels
+
+	LevelWarnings []string `json:"level_warnings,omitempty"` // Sanity-check findings for the prices against the computed levels
+
+	Ensemble *EnsembleResult `json:"ensemble,omitempty"` // Member votes and agreement, for ensemble signals
+
+	PriceAdjusted  bool          `json:"price_adjusted,omitempty"`  // Prices were changed to satisfy exchange rules
+	OriginalPrices *SignalPrices `json:"original_prices,omitempty"` // Prices returned by the model before adjustment
+	Adjustments    []string      `json:"adjustments,omitempty"`     // Reasons for each price adjustment
+	PriceLimits    *PriceLimits  `json:"price_limits,omitempty"`    // Today's auto-rejection limits
+
+	Validation *SignalValidation `json:"validation,omitempty"` // Outcome of the server-side risk-reward validation
+	Dispatch   *SignalDispatch   `json:"dispatch,omitempty"`   // Whether the signal was pushed to Telegram
+}
+
+// EnsembleResult represents how an ensemble signal was aggregated from its members
+type EnsembleResult struct {
+	Method       string         `json:"method"`        // "majority" or "weighted"
+	Consensus    string         `json:"consensus"`     // Direction with the most votes, before the agreement threshold
+	Agreement    float64        `json:"agreement"`     // Share of the vote behind the consensus, in percent
+	MinAgreement float64        `json:"min_agreement"` // Agreement required to emit BUY or SELL
+	Votes        []EnsembleVote `json:"votes"`
+}
+
+// EnsembleVote represents the signal of one ensemble member run
+type EnsembleVote struct {
+	Strategy    string  `json:"strategy"`
+	Signal      string  `json:"signal,omitempty"`
+	Confidence  int     `json:"confidence,omitempty"`
+	BuyPrice    float64 `json:"buy_price,omitempty"`
+	TargetPrice float64 `json:"target_price,omitempty"`
+	StopLoss    float64 `json:"stop_loss,omitempty"`
+	Error       string  `json:"error,omitempty"` // Why the member failed; failed members do not vote
+}
+
+// SignalDispatch represents the outcome of the alert dispatch policy for a signal
+type SignalDispatch struct {
+	Status        string     `json:"status"` // "sent", "suppressed", "below_confidence", "not_actionable" or "failed"
+	ChatID        string     `json:"chat_id,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
+	MinConfidence int        `json:"min_confidence"`
+	CooldownUntil *time.Time `json:"cooldown_until,omitempty"` // End of the push cooldown for the symbol
+}
+
+// SignalValidation represents the outcome of validating a signal's prices and risk-reward
+type SignalValidation struct {
+	Status          string   `json:"status"`                    // "passed", "downgraded", "rejected" or "not_applicable"
+	OriginalSignal  string   `json:"original_signal,omitempty"` // Signal type before a downgrade
+	Risk            float64  `json:"risk,omitempty"`
+	Reward          float64  `json:"reward,omitempty"`
+	RiskRewardRatio float64  `json:"risk_reward_ratio,omitempty"` // Reward divided by risk
+	MinRiskReward   float64  `json:"min_risk_reward"`
+	Issues          []string `json:"issues,omitempty"`
+}
+
+// PriceLimits represents the exchange's daily auto-rejection limits
+type PriceLimits struct {
+	PreviousClose float64 `json:"previous_close"`
+	Percentage    float64 `json:"percentage"`  // Auto-rejection percentage for the price band
+	UpperLimit    float64 `json:"upper_limit"` // Auto-reject upper limit (ARA)
+	LowerLimit    float64 `json:"lower_limit"` // Auto-reject lower limit (ARB)
+}
+
+// SignalPrices holds the entry, target and stop prices of a signal
+type SignalPrices struct {
+	BuyPrice    float64 `json:"buy_price"`
+	TargetPrice float64 `json:"target_price"`
+	StopLoss    float64 `json:"stop_loss"`
+}
+
+// SignalInput holds the market data and derived analysis used to generate a signal for one symbol
+type SignalInput struct {
+	Symbol     Symbol
+	Interval   string
+	Range      string
+	OHLCData   []OHLCData
+	Indicators *TechnicalIndicators
+	Patterns   []CandlestickPattern
+	Levels     *KeyLevels
+
+	PreviousClose float64      // Close of the previous session
+	PriceLimits   *PriceLimits // Today's auto-rejection limits, when the exchange has them
+	MinRiskReward float64      // Minimum reward/risk ratio enforced after generation
+	AsOf          time.Time    // Time the signal is generated for, zero means now (set by backtests)
+}
+
+// Symbol represents a normalized ticker symbol
+type Symbol struct {
+	Code     string `json:"code"`     // Bare ticker code (e.g. "BBCA")
+	Exchange string `json:"exchange"` // Exchange code (e.g. "IDX")
+	Ticker   string `json:"ticker"`   // Market data ticker including suffix (e.g. "BBCA.JK")
+}
+
+// Exchange represents a stock exchange and its regular trading session
+type Exchange struct {
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	Suffix    string `json:"suffix"`     // Ticker suffix used by market data providers (e.g. ".JK")
+	Currency  string `json:"currency"`   // Currency symbol used when formatting prices
+	Timezone  string `json:"timezone"`   // IANA timezone of the exchange
+	OpenTime  string `json:"open_time"`  // Session open in HH:MM local time
+	CloseTime string `json:"close_time"` // Session close in HH:MM local time
 }
 
 // YahooFinanceResponse represents the response from Yahoo Finance API
@@ -65,21 +252,104 @@ type APIResponse struct {
 // SignalRequest represents a request to generate a trading signal
 type SignalRequest struct {
 	StockSymbol string `json:"stock_symbol"`
+	Interval    string `json:"interval,omitempty"` // Candle interval (1m, 5m, 15m, 1h, 1d)
+	Range       string `json:"range,omitempty"`    // Candle lookback range (e.g. 2d, 5d, 1mo)
+	ChatID      string `json:"chat_id,omitempty"`  // Telegram chat to push the signal to (defaults to TELEGRAM_CHAT_ID)
 }
 
 // Config represents application configuration
 type Config struct {
-	GeminiAPIKey       string
-	TelegramBotToken   string
-	TelegramChatID     string
-	Port               string
-	Environment        string
-	DefaultStockSymbol string
-	SignalCooldownMins int
-	MinConfidenceLevel int
-	StockSymbols       []string // List of stock symbols to analyze
-	WebhookURL         string   // Telegram webhook URL
-	CronScheduleTimes  []string // List of cron schedule times in HH:MM format
+	GeminiAPIKey        string
+	TelegramBotToken    string
+	TelegramChatID      string
+	Port                string
+	Environment         string
+	DefaultStockSymbol  string
+	SignalCooldownMins  int
+	MinConfidenceLevel  int
+	StockSymbols        []string // List of stock symbols to analyze
+	KnownSymbols        []string // Additional symbols accepted for single-stock requests
+	AllowUnknownSymbols bool     // Accept symbols that are not in the known-symbol list
+	WebhookURL          string   // Telegram webhook URL
+	CronScheduleTimes   []string // List of cron schedule times in HH:MM format
+
+	// Market data configuration
+	MarketDataProvider        string            // Default market data provider name (e.g. "yahoo")
+	MarketDataSymbolProviders map[string]string // Per-symbol market data provider overrides
+	MarketDataCSVDir          string            // Directory with CSV candle files for the "csv" provider
+	MarketDataHTTPURL         string            // Endpoint for the "http" provider
+
+	// Timeouts of the stages of a signal, zero for none
+	MarketDataTimeoutSecs int // Seconds allowed for each market data fetch
+	StrategyTimeoutSecs   int // Seconds allowed for a strategy run, including model calls and repairs
+
+	// Retry and circuit breaker configuration of Yahoo, the LLM providers and Telegram
+	RetryMaxAttempts        int // Attempts per external call including the first
+	RetryBaseDelayMs        int // Backoff before the first retry in milliseconds, doubled per retry
+	RetryMaxDelaySecs       int // Upper bound of the backoff and of a server-requested delay
+	CircuitFailureThreshold int // Consecutive failed calls that open a dependency's breaker, 0 disables
+	CircuitCooldownSecs     int // Seconds an open breaker rejects calls before a trial call
+
+	// Bulk analysis configuration
+	BulkConcurrency      int // Stocks analyzed in parallel by a bulk run
+	YahooRequestsPerMin  int // Yahoo Finance requests per minute, 0 for no limit
+	GeminiRequestsPerMin int // Gemini requests per minute, 0 for no limit
+	GeminiTokensPerMin   int // Estimated Gemini prompt tokens per minute, 0 for no limit
+	BulkMaxRunningJobs   int // Bulk jobs running at once; further requests attach to a running job
+	BulkMaxJobsPerChat   int // Bulk jobs one chat may have running at once
+
+	// Candle configuration
+	CandleInterval        string            // Default candle interval (e.g. "5m")
+	CandleRange           string            // Default candle lookback range (e.g. "2d")
+	SymbolCandleIntervals map[string]string // Per-symbol candle interval overrides
+	SymbolCandleRanges    map[string]string // Per-symbol candle range overrides
+
+	// Candlestick pattern configuration
+	PatternLookback  int  // Number of recent candles scanned for patterns
+	PatternPrefilter bool // Skip the AI call and return WAIT when no pattern is found
+
+	PriceLimitMode string // How targets/stops beyond ARA/ARB are handled: "clamp" or "reject"
+
+	// LLM provider configuration
+	GeminiLLM     LLMSettings // Model and sampling parameters for Gemini
+	OpenAIBaseURL string      // OpenAI-compatible API base URL, empty to disable the openai strategy
+	OpenAIAPIKey  string      // API key for the OpenAI-compatible endpoint, optional for local servers
+	OpenAILLM     LLMSettings // Model and sampling parameters for the OpenAI-compatible endpoint
+
+	OpenAIStructuredOutput bool // Request responses with the signal JSON schema from the OpenAI-compatible endpoint
+	LLMRepairAttempts      int  // Times a response that fails schema validation is sent back for repair
+
+	// Strategy configuration
+	Strategy         string            // Default signal strategy (e.g. "gemini", "ema_crossover")
+	SymbolStrategies map[string]string // Per-symbol strategy overrides
+	FallbackStrategy string            // Strategy used when the selected one fails, empty to disable
+
+	// Ensemble strategy configuration
+	EnsembleStrategies   []string // Members of the "ensemble" strategy, as name or name:samples
+	EnsembleMethod       string   // "majority" or "weighted"
+	EnsembleMinAgreement float64  // Percent of the vote needed to emit BUY or SELL
+
+	MinRiskRewardRatio float64 // Minimum reward/risk ratio for BUY/SELL signals
+
+	SignalHistoryEnabled bool   // Record generated signals and summaries in the signal history
+	SignalStorePath      string // BoltDB file for the signal history
+
+	// Outcome tracking configuration
+	OutcomeTrackingEnabled bool // Poll candles after stored BUY/SELL signals to record their outcome
+	OutcomePollMinutes     int  // Minutes between outcome tracking runs
+	OutcomeTrackingDays    int  // Days after generation a signal is tracked before it expires
+
+	// Alert dispatch overrides of MinConfidenceLevel and SignalCooldownMins
+	SymbolMinConfidence map[string]int // Per-symbol minimum confidence
+	SymbolCooldownMins  map[string]int // Per-symbol push cooldown in minutes
+	ChatMinConfidence   map[string]int // Per-chat minimum confidence
+	ChatCooldownMins    map[string]int // Per-chat push cooldown in minutes
+
+	// Outbound webhook notifier configuration
+	NotifyWebhookURLs           []string // URLs that receive signals and summaries as signed JSON, empty to disable
+	NotifyWebhookSecret         string   // HMAC-SHA256 key of the X-Signature-256 header
+	NotifyWebhookTimeoutSecs    int      // Seconds allowed for each delivery attempt
+	NotifyWebhookDeadLetterPath string   // JSON Lines file of deliveries that failed every attempt
 }
 
 // SignalSummary represents a summary of all analyzed signals
@@ -92,14 +362,234 @@ type SignalSummary struct {
 	GeneratedAt   time.Time        `json:"generated_at"`
 }
 
+// SignalRecord represents a generated signal stored in the signal history
+type SignalRecord struct {
+	ID               string              `json:"id"`
+	Symbol           string              `json:"symbol"` // Bare ticker code (e.g. "BBCA")
+	Ticker           string              `json:"ticker"` // Market data ticker (e.g. "BBCA.JK")
+	SignalType       string              `json:"signal_type"`
+	Confidence       int                 `json:"confidence"`
+	Model            string              `json:"model,omitempty"`
+	PromptHash       string              `json:"prompt_hash,omitempty"`
+	ValidationStatus string              `json:"validation_status,omitempty"`
+	Inputs           *SignalRecordInputs `json:"inputs"`
+	Signal           *TradingSignal      `json:"signal"`
+	CreatedAt        time.Time           `json:"created_at"`
+}
+
+// SignalRecordInputs summarizes the market data a stored signal was generated from
+type SignalRecordInputs struct {
+	Provider      string    `json:"provider,omitempty"` // Market data provider name
+	Interval      string    `json:"interval"`
+	Range         string    `json:"range"`
+	CandleCount   int       `json:"candle_count"`
+	FirstCandle   time.Time `json:"first_candle"`
+	LastCandle    time.Time `json:"last_candle"`
+	LastClose     float64   `json:"last_close"`
+	PreviousClose float64   `json:"previous_close,omitempty"`
+}
+
+// SummaryRecord represents a bulk analysis summary stored in the signal history
+type SummaryRecord struct {
+	ID        string         `json:"id"`
+	Mode      string         `json:"mode"` // "signals" when each signal was pushed, "summary" when only the summary was
+	Summary   *SignalSummary `json:"summary"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// SignalOutcome represents what happened after a BUY or SELL signal was generated
+type SignalOutcome struct {
+	SignalID    string    `json:"signal_id"`
+	Symbol      string    `json:"symbol"`
+	Exchange    string    `json:"exchange,omitempty"`
+	SignalType  string    `json:"signal_type"`
+	GeneratedAt time.Time `json:"generated_at"`
+	EntryPrice  float64   `json:"entry_price"`
+	TargetPrice float64   `json:"target_price"`
+	StopLoss    float64   `json:"stop_loss"`
+
+	Status        string     `json:"status"` // "pending", "open", "target_hit", "stop_hit", "not_filled" or "expired"
+	EntryFilled   bool       `json:"entry_filled"`
+	EntryFilledAt *time.Time `json:"entry_filled_at,omitempty"`
+	ExitPrice     float64    `json:"exit_price,omitempty"`
+	OutcomeAt     *time.Time `json:"outcome_at,omitempty"`
+
+	TimeToOutcomeMinutes float64 `json:"time_to_outcome_minutes,omitempty"` // From generation to the target, stop or expiry
+	MaxFavorable         float64 `json:"max_favorable_excursion"`           // Best price move after the fill, in price units
+	MaxAdverse           float64 `json:"max_adverse_excursion"`             // Worst price move after the fill, in price units
+	MaxFavorablePct      float64 `json:"max_favorable_excursion_pct"`
+	MaxAdversePct        float64 `json:"max_adverse_excursion_pct"`
+	ReturnPct            float64 `json:"return_pct,omitempty"` // Return of a closed trade relative to the entry
+
+	LastCandleAt  *time.Time `json:"last_candle_at,omitempty"` // Latest candle evaluated
+	LastCheckedAt time.Time  `json:"last_checked_at"`
+}
+
+// OutcomeStats represents aggregate outcomes of tracked signals
+type OutcomeStats struct {
+	Tracked    int `json:"tracked"`
+	Pending    int `json:"pending"` // Waiting for the entry to fill
+	Open       int `json:"open"`    // Filled, waiting for the target or stop
+	NotFilled  int `json:"not_filled"`
+	TargetHits int `json:"target_hits"`
+	StopHits   int `json:"stop_hits"`
+	Expired    int `json:"expired"` // Filled but neither target nor stop reached in time
+
+	FillRate             float64 `json:"fill_rate"` // Filled share of signals whose entry window is over, in percent
+	HitRate              float64 `json:"hit_rate"`  // Target hits as a share of target and stop hits, in percent
+	AvgReturnPct         float64 `json:"avg_return_pct"`
+	AvgMaxFavorablePct   float64 `json:"avg_max_favorable_excursion_pct"`
+	AvgMaxAdversePct     float64 `json:"avg_max_adverse_excursion_pct"`
+	AvgTimeToOutcomeMins float64 `json:"avg_time_to_outcome_minutes"`
+}
+
+// SignalQuery filters stored signals and summaries. Zero values do not filter.
+type SignalQuery struct {
+	Symbol        string    `form:"symbol"`
+	SignalType    string    `form:"signal"`
+	MinConfidence int       `form:"min_confidence"`
+	MaxConfidence int       `form:"max_confidence"`
+	From          time.Time `form:"-"`
+	To            time.Time `form:"-"`
+	Limit         int       `form:"limit"`
+	SignalTypes   []string  `form:"-"` // Any of these signal types, e.g. BUY and SELL
+	BeforeID      string    `form:"-"` // Only records older than this signal ID, for paging
+}
+
+// BacktestRequest describes a backtest run. Zero values fall back to the configuration.
+type BacktestRequest struct {
+	Symbols       []string  `json:"symbols"`        // Defaults to the configured stock symbols
+	Strategy      string    `json:"strategy"`       // "recorded" (default) or a registered strategy name
+	Interval      string    `json:"interval"`       // Candle interval, as for GenerateSignal
+	Range         string    `json:"range"`          // Lookback window of each run, as for GenerateSignal
+	From          time.Time `json:"from"`           // First scheduled run
+	To            time.Time `json:"to"`             // Last scheduled run, defaults to now
+	ScheduleTimes []string  `json:"schedule_times"` // Run times in HH:MM WIB, defaults to the cron times
+	HoldingDays   int       `json:"holding_days"`   // How long a signal may wait for its fill and exit
+}
+
+// BacktestStats represents the performance of the simulated trades
+type BacktestStats struct {
+	Signals        int     `json:"signals"` // BUY/SELL signals simulated
+	Trades         int     `json:"trades"`  // Filled trades that reached the target, the stop or expiry
+	Wins           int     `json:"wins"`
+	Losses         int     `json:"losses"`
+	WinRate        float64 `json:"win_rate"`         // Share of trades with a positive return, in percent
+	ExpectancyPct  float64 `json:"expectancy_pct"`   // Average return per trade
+	TotalReturnPct float64 `json:"total_return_pct"` // Trade returns compounded in exit order
+	ProfitFactor   float64 `json:"profit_factor"`    // Gross profit over gross loss, 0 without losing trades
+	MaxDrawdownPct float64 `json:"max_drawdown_pct"` // Largest peak-to-trough fall of the compounded equity
+	SharpeRatio    float64 `json:"sharpe_ratio"`     // Mean over standard deviation of trade returns, not annualized
+}
+
+// BacktestSymbolResult represents the backtest of one symbol
+type BacktestSymbolResult struct {
+	Symbol      string         `json:"symbol"`
+	Interval    string         `json:"interval"`
+	Range       string         `json:"range"`
+	Runs        int            `json:"runs"` // Scheduled runs that saw a new candle
+	BuySignals  int            `json:"buy_signals"`
+	SellSignals int            `json:"sell_signals"`
+	WaitSignals int            `json:"wait_signals"`
+	Skipped     int            `json:"skipped"` // Runs with a position still open or without a recorded signal
+	Failed      int            `json:"failed"`
+	Stats       *BacktestStats `json:"stats"`
+}
+
+// BacktestResult represents the result of a backtest run
+type BacktestResult struct {
+	Request  BacktestRequest         `json:"request"`
+	Strategy string                  `json:"strategy"`
+	Symbols  []*BacktestSymbolResult `json:"symbols"`
+	Trades   []*SignalOutcome        `json:"trades"`
+	Stats    *BacktestStats          `json:"stats"`
+	Outcomes *OutcomeStats           `json:"outcomes"` // Fill and hit rates of the simulated signals
+	Errors   []string                `json:"errors,omitempty"`
+}
+
 // BulkSignalResult represents the result of bulk signal analysis
 type BulkSignalResult struct {
-	JobID       string         `json:"job_id"`
-	Status      string         `json:"status"` // "started", "completed", "failed"
-	Message     string         `json:"message"`
-	Summary     *SignalSummary `json:"summary,omitempty"`
-	StartedAt   time.Time      `json:"started_at"`
-	CompletedAt *time.Time     `json:"completed_at,omitempty"`
+	JobID       string               `json:"job_id"`
+	Mode        string               `json:"mode"`     // "signals" pushes each signal, "summary" only the summary
+	ChatIDs     []string             `json:"chat_ids"` // Chats that receive the result; the first started the job
+	Status      string               `json:"status"`   // "running", "completed", "cancelled"
+	Message     string               `json:"message"`
+	Total       int                  `json:"total"`
+	Analyzed    int                  `json:"analyzed"`
+	Progress    []BulkSymbolProgress `json:"progress"`
+	Summary     *SignalSummary       `json:"summary,omitempty"` // Set once the job completes
+	StartedAt   time.Time            `json:"started_at"`
+	CompletedAt *time.Time           `json:"completed_at,omitempty"`
+}
+
+// BulkSymbolProgress represents the progress of one symbol in a bulk analysis job
+type BulkSymbolProgress struct {
+	Symbol     string `json:"symbol"`
+	Status     string `json:"status"`          // "pending", "running", "done", "failed"
+	Stage      string `json:"stage,omitempty"` // Stage being run: "fetch_candles", "previous_close", "strategy", "validation"
+	Signal     string `json:"signal,omitempty"`
+	Confidence int    `json:"confidence,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// SignalFilter selects the signals pushed to a signal feed subscriber; empty fields match every signal
+type SignalFilter struct {
+	Symbols       []string `json:"symbols,omitempty"` // Codes or tickers, e.g. "BBCA" or "BBCA.JK"
+	Signals       []string `json:"signals,omitempty"` // "BUY", "SELL", "WAIT"
+	MinConfidence int      `json:"min_confidence,omitempty"`
+}
+
+// SignalFeedMessage represents a message sent to a signal feed subscriber
+type SignalFeedMessage struct {
+	Type   string         `json:"type"`          // "subscribed", "signal" or "error"
+	Seq    uint64         `json:"seq,omitempty"` // Position of the signal in the feed, for resuming after a reconnect
+	Signal *TradingSignal `json:"signal,omitempty"`
+	Filter *SignalFilter  `json:"filter,omitempty"` // Filter in effect, for subscribed messages
+	Error  string         `json:"error,omitempty"`
+	Time   time.Time      `json:"time"`
+}
+
+// WebhookPayload is the JSON body posted to notification webhooks
+type WebhookPayload struct {
+	ID      string         `json:"id"`                 // Delivery ID, unchanged across retries
+	Event   string         `json:"event"`              // "signal" or "summary"
+	ChatIDs []string       `json:"chat_ids,omitempty"` // Chats the signal or summary was made for
+	Signal  *TradingSignal `json:"signal,omitempty"`
+	Summary *SignalSummary `json:"summary,omitempty"`
+	SentAt  time.Time      `json:"sent_at"`
+}
+
+// WebhookDeadLetter records a webhook delivery that failed every attempt, with the body and
+// signature exactly as sent so that it can be replayed
+type WebhookDeadLetter struct {
+	URL       string          `json:"url"`
+	Event     string          `json:"event"`
+	Delivery  string          `json:"delivery"`
+	Signature string          `json:"signature"`
+	Payload   json.RawMessage `json:"payload"`
+	Error     string          `json:"error"`
+	FailedAt  time.Time       `json:"failed_at"`
+}
+
+// JobEvent represents a change in a bulk analysis job, streamed to subscribers
+type JobEvent struct {
+	Type   string            `json:"type"` // "job_started", "symbol_stage", "signal", "symbol_failed", "job_completed", "job_cancelled"
+	JobID  string            `json:"job_id"`
+	Symbol string            `json:"symbol,omitempty"`
+	Stage  string            `json:"stage,omitempty"`
+	Signal *TradingSignal    `json:"signal,omitempty"`
+	Error  string            `json:"error,omitempty"`
+	Job    *BulkSignalResult `json:"job,omitempty"` // State of the job, for job events
+	Time   time.Time         `json:"time"`
+}
+
+// DependencyStatus represents the circuit breaker state of an external service
+type DependencyStatus struct {
+	Name                string     `json:"name"`
+	State               string     `json:"state"` // closed, open or half_open
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"` // When an open breaker lets a trial call through
 }
 
 // TelegramWebhook represents incoming webhook from Telegram
@@ -152,3 +642,11 @@ type TelegramInlineKeyboardButton struct {
 	Text         string `json:"text"`
 	CallbackData string `json:"callback_data,omitempty"`
 }
+
+// LLMSettings represents the model and sampling parameters of an LLM provider
+type LLMSettings struct {
+	Model       string
+	Temperature float64
+	TopP        float64 // 0 leaves the provider default
+	TopK        int     // 0 leaves the provider default
+}
@@ -0,0 +1,224 @@
+// Package patterns detects candlestick patterns in OHLC candle data.
+package patterns
+
+import (
+	"math"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// Pattern names returned by Detect
+const (
+	BullishEngulfing = "bullish_engulfing"
+	BearishEngulfing = "bearish_engulfing"
+	Doji             = "doji"
+	Hammer           = "hammer"
+	ShootingStar     = "shooting_star"
+	MorningStar      = "morning_star"
+	EveningStar      = "evening_star"
+	InsideBar        = "inside_bar"
+	BullishMarubozu  = "bullish_marubozu"
+	BearishMarubozu  = "bearish_marubozu"
+)
+
+// Pattern directions
+const (
+	Bullish = "bullish"
+	Bearish = "bearish"
+	Neutral = "neutral"
+)
+
+// trendLookback is the number of candles used to determine the trend preceding a pattern
+const trendLookback = 3
+
+// candle holds the derived measurements of a single candle
+type candle struct {
+	models.OHLCData
+	body        float64
+	totalRange  float64
+	upperShadow float64
+	lowerShadow float64
+}
+
+func newCandle(data models.OHLCData) candle {
+	return candle{
+		OHLCData:    data,
+		body:        math.Abs(data.Close - data.Open),
+		totalRange:  data.High - data.Low,
+		upperShadow: data.High - math.Max(data.Open, data.Close),
+		lowerShadow: math.Min(data.Open, data.Close) - data.Low,
+	}
+}
+
+func (c candle) bullish() bool { return c.Close > c.Open }
+func (c candle) bearish() bool { return c.Close < c.Open }
+func (c candle) midpoint() float64 {
+	return (c.Open + c.Close) / 2
+}
+
+// Detect scans all candles and returns every detected pattern in candle order
+func Detect(data []models.OHLCData) []models.CandlestickPattern {
+	return DetectRecent(data, len(data))
+}
+
+// DetectRecent returns the patterns completed within the last lookback candles
+func DetectRecent(data []models.OHLCData, lookback int) []models.CandlestickPattern {
+	candles := make([]candle, len(data))
+	for i, d := range data {
+		candles[i] = newCandle(d)
+	}
+
+	start := len(candles) - lookback
+	if start < 0 {
+		start = 0
+	}
+
+	var detected []models.CandlestickPattern
+	for i := start; i < len(candles); i++ {
+		if candles[i].totalRange <= 0 {
+			continue
+		}
+
+		for _, detector := range detectors {
+			if name, direction, strength, ok := detector(candles, i); ok {
+				detected = append(detected, models.CandlestickPattern{
+					Name:      name,
+					Direction: direction,
+					Index:     i,
+					Timestamp: candles[i].Timestamp,
+					Strength:  clampStrength(strength),
+				})
+			}
+		}
+	}
+
+	return detected
+}
+
+// detector checks whether a pattern completes at index i and returns its name, direction and strength (0-100)
+type detector func(candles []candle, i int) (string, string, float64, bool)
+
+var detectors = []detector{
+	detectEngulfing,
+	detectDoji,
+	detectHammer,
+	detectShootingStar,
+	detectStar,
+	detectInsideBar,
+	detectMarubozu,
+}
+
+// detectEngulfing detects a candle whose body fully engulfs the opposite-colored previous body
+func detectEngulfing(candles []candle, i int) (string, string, float64, bool) {
+	if i < 1 {
+		return "", "", 0, false
+	}
+	prev, cur := candles[i-1], candles[i]
+	if prev.body == 0 || cur.body <= prev.body {
+		return "", "", 0, false
+	}
+
+	strength := 50 * cur.body / prev.body
+	if prev.bearish() && cur.bullish() && cur.Open <= prev.Close && cur.Close >= prev.Open {
+		return BullishEngulfing, Bullish, strength, true
+	}
+	if prev.bullish() && cur.bearish() && cur.Open >= prev.Close && cur.Close <= prev.Open {
+		return BearishEngulfing, Bearish, strength, true
+	}
+	return "", "", 0, false
+}
+
+// detectDoji detects a candle with a very small body relative to its range
+func detectDoji(candles []candle, i int) (string, string, float64, bool) {
+	cur := candles[i]
+	if cur.body > 0.1*cur.totalRange {
+		return "", "", 0, false
+	}
+	return Doji, Neutral, 100 * (1 - cur.body/(0.1*cur.totalRange)), true
+}
+
+// detectHammer detects a small body with a long lower shadow after a decline
+func detectHammer(candles []candle, i int) (string, string, float64, bool) {
+	cur := candles[i]
+	if cur.body == 0 || cur.lowerShadow < 2*cur.body || cur.upperShadow > 0.1*cur.totalRange {
+		return "", "", 0, false
+	}
+	if !priorTrend(candles, i, Bearish) {
+		return "", "", 0, false
+	}
+	return Hammer, Bullish, 100 * cur.lowerShadow / cur.totalRange, true
+}
+
+// detectShootingStar detects a small body with a long upper shadow after a rally
+func detectShootingStar(candles []candle, i int) (string, string, float64, bool) {
+	cur := candles[i]
+	if cur.body == 0 || cur.upperShadow < 2*cur.body || cur.lowerShadow > 0.1*cur.totalRange {
+		return "", "", 0, false
+	}
+	if !priorTrend(candles, i, Bullish) {
+		return "", "", 0, false
+	}
+	return ShootingStar, Bearish, 100 * cur.upperShadow / cur.totalRange, true
+}
+
+// detectStar detects the three-candle morning and evening star reversals
+func detectStar(candles []candle, i int) (string, string, float64, bool) {
+	if i < 2 {
+		return "", "", 0, false
+	}
+	first, middle, last := candles[i-2], candles[i-1], candles[i]
+	if first.totalRange == 0 || first.body < 0.6*first.totalRange || middle.body > 0.3*first.body {
+		return "", "", 0, false
+	}
+
+	if first.bearish() && last.bullish() && last.Close > first.midpoint() {
+		return MorningStar, Bullish, 50 + 50*(last.Close-first.midpoint())/(first.body/2), true
+	}
+	if first.bullish() && last.bearish() && last.Close < first.midpoint() {
+		return EveningStar, Bearish, 50 + 50*(first.midpoint()-last.Close)/(first.body/2), true
+	}
+	return "", "", 0, false
+}
+
+// detectInsideBar detects a candle whose range is inside the previous candle's range
+func detectInsideBar(candles []candle, i int) (string, string, float64, bool) {
+	if i < 1 {
+		return "", "", 0, false
+	}
+	prev, cur := candles[i-1], candles[i]
+	if prev.totalRange == 0 || cur.High > prev.High || cur.Low < prev.Low {
+		return "", "", 0, false
+	}
+	return InsideBar, Neutral, 100 * (1 - cur.totalRange/prev.totalRange), true
+}
+
+// detectMarubozu detects a candle with almost no shadows
+func detectMarubozu(candles []candle, i int) (string, string, float64, bool) {
+	cur := candles[i]
+	if cur.body < 0.95*cur.totalRange {
+		return "", "", 0, false
+	}
+
+	strength := 100 * cur.body / cur.totalRange
+	if cur.bullish() {
+		return BullishMarubozu, Bullish, strength, true
+	}
+	return BearishMarubozu, Bearish, strength, true
+}
+
+// priorTrend reports whether the closes before index i moved in the given direction
+func priorTrend(candles []candle, i int, direction string) bool {
+	if i < trendLookback {
+		return false
+	}
+	from, to := candles[i-trendLookback].Close, candles[i-1].Close
+	if direction == Bullish {
+		return to > from
+	}
+	return to < from
+}
+
+// clampStrength rounds a strength score into the 0-100 range
+func clampStrength(strength float64) int {
+	return int(math.Round(math.Max(0, math.Min(100, strength))))
+}
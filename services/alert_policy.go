@@ -0,0 +1,145 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// Dispatch statuses recorded on signals
+const (
+	DispatchSent            = "sent"
+	DispatchSuppressed      = "suppressed"
+	DispatchBelowConfidence = "below_confidence"
+	DispatchNotActionable   = "not_actionable"
+	DispatchFailed          = "failed"
+)
+
+// AlertPolicy decides whether a generated signal is pushed to a chat. BUY/SELL signals are
+// pushed when their confidence reaches the minimum and no signal for the same symbol was
+// pushed to the chat within the cooldown. Per-symbol settings take precedence over per-chat
+// settings, which take precedence over the global settings.
+type AlertPolicy struct {
+	minConfidence       int
+	cooldown            time.Duration
+	symbolMinConfidence map[string]int
+	symbolCooldowns     map[string]time.Duration
+	chatMinConfidence   map[string]int
+	chatCooldowns       map[string]time.Duration
+
+	lastSent map[string]time.Time // Keyed by chat ID and ticker
+	mutex    sync.Mutex
+}
+
+// NewAlertPolicy creates an alert policy from the configuration
+func NewAlertPolicy(config *models.Config) *AlertPolicy {
+	return &AlertPolicy{
+		minConfidence:       config.MinConfidenceLevel,
+		cooldown:            time.Duration(config.SignalCooldownMins) * time.Minute,
+		symbolMinConfidence: config.SymbolMinConfidence,
+		symbolCooldowns:     minuteDurations(config.SymbolCooldownMins),
+		chatMinConfidence:   config.ChatMinConfidence,
+		chatCooldowns:       minuteDurations(config.ChatCooldownMins),
+		lastSent:            make(map[string]time.Time),
+	}
+}
+
+// Reserve decides whether a signal may be pushed to a chat. When it may, the returned
+// dispatch has status "sent" and the symbol's cooldown starts immediately so concurrent
+// runs cannot push it twice; call Release if the push then fails.
+func (p *AlertPolicy) Reserve(chatID string, signal *models.TradingSignal, symbol models.Symbol) *models.SignalDispatch {
+	minConfidence := p.minConfidenceFor(chatID, symbol)
+	dispatch := &models.SignalDispatch{
+		ChatID:        chatID,
+		MinConfidence: minConfidence,
+	}
+
+	direction := strings.ToUpper(signal.Signal)
+	if direction != "BUY" && direction != "SELL" {
+		dispatch.Status = DispatchNotActionable
+		dispatch.Reason = fmt.Sprintf("%s signals are not pushed", direction)
+		return dispatch
+	}
+
+	if signal.Confidence < minConfidence {
+		dispatch.Status = DispatchBelowConfidence
+		dispatch.Reason = fmt.Sprintf("confidence %d%% is below the minimum of %d%%", signal.Confidence, minConfidence)
+		return dispatch
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	key := alertKey(chatID, symbol)
+	if lastSent, exists := p.lastSent[key]; exists {
+		until := lastSent.Add(p.cooldownFor(chatID, symbol))
+		if now.Before(until) {
+			dispatch.Status = DispatchSuppressed
+			dispatch.Reason = fmt.Sprintf("a %s signal was already sent at %s", symbol.Ticker, lastSent.Format("15:04:05"))
+			dispatch.CooldownUntil = &until
+			return dispatch
+		}
+	}
+
+	until := now.Add(p.cooldownFor(chatID, symbol))
+	p.lastSent[key] = now
+	dispatch.Status = DispatchSent
+	dispatch.CooldownUntil = &until
+	return dispatch
+}
+
+// Release clears the cooldown started by Reserve after a failed push
+func (p *AlertPolicy) Release(chatID string, symbol models.Symbol) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.lastSent, alertKey(chatID, symbol))
+}
+
+// minConfidenceFor returns the minimum confidence for a symbol in a chat
+func (p *AlertPolicy) minConfidenceFor(chatID string, symbol models.Symbol) int {
+	if value, exists := lookupSymbolSetting(p.symbolMinConfidence, symbol); exists {
+		return value
+	}
+	if value, exists := p.chatMinConfidence[chatID]; exists {
+		return value
+	}
+	return p.minConfidence
+}
+
+// cooldownFor returns the push cooldown for a symbol in a chat
+func (p *AlertPolicy) cooldownFor(chatID string, symbol models.Symbol) time.Duration {
+	if value, exists := lookupSymbolSetting(p.symbolCooldowns, symbol); exists {
+		return value
+	}
+	if value, exists := p.chatCooldowns[chatID]; exists {
+		return value
+	}
+	return p.cooldown
+}
+
+// alertKey identifies the cooldown of a symbol in a chat
+func alertKey(chatID string, symbol models.Symbol) string {
+	return chatID + "|" + symbol.Ticker
+}
+
+// minuteDurations converts a map of minute counts to durations
+func minuteDurations(minutes map[string]int) map[string]time.Duration {
+	durations := make(map[string]time.Duration, len(minutes))
+	for key, value := range minutes {
+		durations[key] = time.Duration(value) * time.Minute
+	}
+	return durations
+}
+
+// lookupSymbolSetting looks up a typed per-symbol setting by ticker first and then by bare code
+func lookupSymbolSetting[T any](settings map[string]T, symbol models.Symbol) (T, bool) {
+	if value, exists := settings[symbol.Ticker]; exists {
+		return value, true
+	}
+	value, exists := settings[symbol.Code]
+	return value, exists
+}
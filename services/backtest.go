@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// ErrInvalidBacktest is returned when a backtest request cannot be run
+var ErrInvalidBacktest = errors.New("invalid backtest request")
+
+// DefaultBacktestHoldingDays is how long a backtested signal may wait for its fill and exit
+// when neither the request nor OUTCOME_TRACKING_DAYS set it
+const DefaultBacktestHoldingDays = 5
+
+// defaultBacktestScheduleTimes are the run times used when the request and the cron have none
+var defaultBacktestScheduleTimes = []string{"09:30"}
+
+// RunBacktest replays historical candles through the signal pipeline at every scheduled time in
+// the request's date range and simulates the resulting BUY/SELL signals. Cancelling ctx stops
+// the backtest with ctx's error.
+func (t *TradingSignalService) RunBacktest(ctx context.Context, request models.BacktestRequest) (*models.BacktestResult, error) {
+	strategy, err := t.backtestStrategy(request.Strategy)
+	if err != nil {
+		return nil, err
+	}
+	return t.runBacktest(ctx, request, strategy)
+}
+
+// backtestStrategy returns the strategy a backtest replays: the recorded signals by default,
+// or any registered strategy by name
+func (t *TradingSignalService) backtestStrategy(name string) (Strategy, error) {
+	if name = strings.ToLower(strings.TrimSpace(name)); name == "" || name == "recorded" {
+		if t.store == nil {
+			return nil, fmt.Errorf("the recorded strategy replays the signal history: %w", ErrSignalHistoryDisabled)
+		}
+		return NewRecordedStrategy(t.store), nil
+	}
+
+	strategy, err := t.strategies.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBacktest, err)
+	}
+	return strategy, nil
+}
+
+// runBacktest runs a backtest with the given strategy
+func (t *TradingSignalService) runBacktest(ctx context.Context, request models.BacktestRequest, strategy Strategy) (*models.BacktestResult, error) {
+	now := time.Now()
+	if request.To.IsZero() || request.To.After(now) {
+		request.To = now
+	}
+	if request.From.IsZero() || !request.From.Before(request.To) {
+		return nil, fmt.Errorf("%w: from must be set and before to", ErrInvalidBacktest)
+	}
+	if len(request.Symbols) == 0 {
+		request.Symbols = t.config.StockSymbols
+	}
+	if len(request.Symbols) == 0 {
+		return nil, fmt.Errorf("%w: no symbols to backtest", ErrInvalidBacktest)
+	}
+	if len(request.ScheduleTimes) == 0 {
+		request.ScheduleTimes = t.config.CronScheduleTimes
+	}
+	if len(request.ScheduleTimes) == 0 {
+		request.ScheduleTimes = defaultBacktestScheduleTimes
+	}
+	if request.HoldingDays <= 0 {
+		request.HoldingDays = t.config.OutcomeTrackingDays
+	}
+	if request.HoldingDays <= 0 {
+		request.HoldingDays = DefaultBacktestHoldingDays
+	}
+	request.Strategy = strategy.Name()
+
+	runTimes, err := backtestRunTimes(request.From, request.To, request.ScheduleTimes)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Starting %s backtest for %d symbols over %d scheduled runs", strategy.Name(), len(request.Symbols), len(runTimes))
+
+	result := &models.BacktestResult{
+		Request:  request,
+		Strategy: strategy.Name(),
+	}
+	for _, rawSymbol := range request.Symbols {
+		symbolResult, trades, err := t.backtestSymbol(ctx, rawSymbol, request, strategy, runTimes)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("backtest cancelled: %w", ctx.Err())
+		}
+		if err != nil {
+			log.Printf("Backtest failed for %s: %v", rawSymbol, err)
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", rawSymbol, err))
+			continue
+		}
+		result.Symbols = append(result.Symbols, symbolResult)
+		result.Trades = append(result.Trades, trades...)
+	}
+
+	result.Stats = computeBacktestStats(result.Trades)
+	result.Outcomes = SummarizeOutcomes(result.Trades)
+
+	log.Printf("Backtest completed: %d signals, %d trades, win rate %.1f%%", result.Stats.Signals, result.Stats.Trades, result.Stats.WinRate)
+	return result, nil
+}
+
+// backtestSymbol replays one symbol. At each run the strategy sees the candles of the lookback
+// range that had closed by then, and a signal occupies the symbol until its outcome is known.
+func (t *TradingSignalService) backtestSymbol(ctx context.Context, rawSymbol string, request models.BacktestRequest, strategy Strategy, runTimes []time.Time) (*models.BacktestSymbolResult, []*models.SignalOutcome, error) {
+	symbol, err := t.symbols.Normalize(rawSymbol)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	interval, dataRange, err := t.resolveCandleSettings(symbol, request.Interval, request.Range)
+	if err != nil {
+		return nil, nil, err
+	}
+	lookback, _ := parseRangeDuration(dataRange)
+	candleDuration := intervalDurations[interval]
+	holding := time.Duration(request.HoldingDays) * 24 * time.Hour
+
+	var candles []models.OHLCData
+	err = runStage(ctx, StageFetchCandles, t.marketDataTimeout(), func(ctx context.Context) error {
+		var err error
+		candles, err = t.marketData.FetchCandles(ctx, symbol, interval, backtestFetchRange(interval, request.From.Add(-lookback)))
+		return err
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch OHLC data: %w", err)
+	}
+	if len(candles) == 0 {
+		return nil, nil, fmt.Errorf("no OHLC data available for %s", symbol.Ticker)
+	}
+	if candles[0].Timestamp.After(request.From.Add(-lookback)) {
+		log.Printf("Backtest history for %s starts at %s, early runs see shorter windows", symbol.Ticker, candles[0].Timestamp.Format(time.RFC3339))
+	}
+	dataEnd := candles[len(candles)-1].Timestamp
+
+	result := &models.BacktestSymbolResult{
+		Symbol:   symbol.Ticker,
+		Interval: interval,
+		Range:    dataRange,
+	}
+	location := exchangeLocation(symbol)
+
+	var trades []*models.SignalOutcome
+	var lastCandle, busyUntil time.Time
+	for _, at := range runTimes {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		window := completedCandles(candles, at.Add(-lookback), at, candleDuration)
+		if len(window) == 0 || !window[len(window)-1].Timestamp.After(lastCandle) {
+			// Nothing new since the previous run, e.g. on weekends and holidays
+			continue
+		}
+		lastCandle = window[len(window)-1].Timestamp
+		result.Runs++
+
+		if at.Before(busyUntil) {
+			result.Skipped++
+			continue
+		}
+
+		var previousClose float64
+		if symbol.Exchange == "IDX" {
+			previousClose = lastCloseBefore(window, location, tradingSessionDate(symbol, at))
+		}
+		input := t.buildSignalInput(symbol, interval, dataRange, window, previousClose, at)
+
+		signal, err := t.evaluateSignal(ctx, strategy, input)
+		if errors.Is(err, ErrNoRecordedSignal) {
+			result.Skipped++
+			continue
+		}
+		if err != nil {
+			log.Printf("Backtest run for %s at %s failed: %v", symbol.Ticker, at.Format(time.RFC3339), err)
+			result.Failed++
+			continue
+		}
+
+		switch strings.ToUpper(signal.Signal) {
+		case "BUY":
+			result.BuySignals++
+		case "SELL":
+			result.SellSignals++
+		default:
+			result.WaitSignals++
+			continue
+		}
+
+		// Signals still undecided when the data ends stay pending or open
+		deadline := at.Add(holding)
+		outcome := EvaluateOutcome(signal, candles, deadline, dataEnd)
+		trades = append(trades, outcome)
+
+		busyUntil = deadline
+		if outcome.OutcomeAt != nil {
+			busyUntil = *outcome.OutcomeAt
+		}
+	}
+
+	result.Stats = computeBacktestStats(trades)
+	return result, trades, nil
+}
+
+// backtestRunTimes lists the scheduled times (HH:MM in WIB) of every day between from and to
+func backtestRunTimes(from, to time.Time, scheduleTimes []string) ([]time.Time, error) {
+	location, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		location = time.UTC
+	}
+
+	var clocks []time.Time
+	for _, scheduleTime := range scheduleTimes {
+		clock, err := time.Parse("15:04", strings.TrimSpace(scheduleTime))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid schedule time %q, expected HH:MM", ErrInvalidBacktest, scheduleTime)
+		}
+		clocks = append(clocks, clock)
+	}
+
+	var runTimes []time.Time
+	start := from.In(location)
+	for day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, location); !day.After(to); day = day.AddDate(0, 0, 1) {
+		for _, clock := range clocks {
+			at := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, location)
+			if !at.Before(from) && !at.After(to) {
+				runTimes = append(runTimes, at)
+			}
+		}
+	}
+
+	sort.Slice(runTimes, func(i, j int) bool { return runTimes[i].Before(runTimes[j]) })
+	return runTimes, nil
+}
+
+// backtestFetchRange returns a range reaching back to start, capped at the interval's limit
+func backtestFetchRange(interval string, start time.Time) string {
+	days := int(math.Ceil(time.Since(start).Hours()/24)) + 1
+	if maxRange, limited := maxIntervalRanges[interval]; limited && days > int(maxRange.Hours()/24) {
+		days = int(maxRange.Hours() / 24)
+	}
+	return fmt.Sprintf("%dd", days)
+}
+
+// completedCandles returns the candles that started at or after from and had closed by at
+func completedCandles(candles []models.OHLCData, from, at time.Time, interval time.Duration) []models.OHLCData {
+	start := sort.Search(len(candles), func(i int) bool {
+		return !candles[i].Timestamp.Before(from)
+	})
+	end := sort.Search(len(candles), func(i int) bool {
+		return candles[i].Timestamp.Add(interval).After(at)
+	})
+	if end <= start {
+		return nil
+	}
+	return candles[start:end]
+}
+
+// computeBacktestStats computes the performance of the trades that were filled and closed
+func computeBacktestStats(trades []*models.SignalOutcome) *models.BacktestStats {
+	stats := &models.BacktestStats{Signals: len(trades)}
+
+	var closed []*models.SignalOutcome
+	for _, trade := range trades {
+		if trade.EntryFilled && trade.OutcomeAt != nil {
+			closed = append(closed, trade)
+		}
+	}
+	if len(closed) == 0 {
+		return stats
+	}
+	sort.SliceStable(closed, func(i, j int) bool { return closed[i].OutcomeAt.Before(*closed[j].OutcomeAt) })
+
+	var sum, grossProfit, grossLoss float64
+	equity, peak := 1.0, 1.0
+	for _, trade := range closed {
+		sum += trade.ReturnPct
+		switch {
+		case trade.ReturnPct > 0:
+			stats.Wins++
+			grossProfit += trade.ReturnPct
+		case trade.ReturnPct < 0:
+			stats.Losses++
+			grossLoss -= trade.ReturnPct
+		}
+
+		equity *= 1 + trade.ReturnPct/100
+		peak = math.Max(peak, equity)
+		stats.MaxDrawdownPct = math.Max(stats.MaxDrawdownPct, (peak-equity)/peak*100)
+	}
+
+	stats.Trades = len(closed)
+	stats.WinRate = float64(stats.Wins) / float64(stats.Trades) * 100
+	stats.ExpectancyPct = sum / float64(stats.Trades)
+	stats.TotalReturnPct = (equity - 1) * 100
+	if grossLoss > 0 {
+		stats.ProfitFactor = grossProfit / grossLoss
+	}
+
+	if stats.Trades > 1 {
+		var variance float64
+		for _, trade := range closed {
+			variance += math.Pow(trade.ReturnPct-stats.ExpectancyPct, 2)
+		}
+		if deviation := math.Sqrt(variance / float64(stats.Trades-1)); deviation > 0 {
+			stats.SharpeRatio = stats.ExpectancyPct / deviation
+		}
+	}
+
+	return stats
+}
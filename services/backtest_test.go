@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// staticMarketData serves a fixed candle series for every symbol
+type staticMarketData struct {
+	candles []models.OHLCData
+}
+
+func (s *staticMarketData) Name() string { return "static" }
+
+func (s *staticMarketData) FetchCandles(ctx context.Context, symbol, interval, dataRange string) ([]models.OHLCData, error) {
+	return s.candles, nil
+}
+
+// stubStrategy buys just above the last close with a fixed target and stop and checks what it saw
+type stubStrategy struct {
+	t       *testing.T
+	candles time.Duration
+}
+
+func (s *stubStrategy) Name() string { return "stub" }
+
+func (s *stubStrategy) GenerateTradingSignal(ctx context.Context, input *models.SignalInput) (*models.TradingSignal, error) {
+	last := input.OHLCData[len(input.OHLCData)-1]
+	if last.Timestamp.Add(s.candles).After(input.AsOf) {
+		s.t.Errorf("run at %s saw the unfinished candle %s", input.AsOf, last.Timestamp)
+	}
+
+	signal := &models.TradingSignal{
+		Signal:      "BUY",
+		BuyPrice:    last.Close + 1,
+		TargetPrice: last.Close + 4,
+		StopLoss:    last.Close,
+		Confidence:  80,
+	}
+	annotateSignal(signal, input)
+	return signal, nil
+}
+
+func TestRunBacktest(t *testing.T) {
+	location, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Skipf("Asia/Jakarta time zone unavailable: %v", err)
+	}
+
+	// Hourly candles over three days that climb one point per hour
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, location)
+	var candles []models.OHLCData
+	for i := 0; i < 72; i++ {
+		price := 100 + float64(i)
+		candles = append(candles, models.OHLCData{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Open:      price,
+			High:      price + 1.5,
+			Low:       price - 0.5,
+			Close:     price + 1,
+			Volume:    1000,
+		})
+	}
+
+	registry := NewMarketDataRegistry("static", nil)
+	registry.Register(&staticMarketData{candles: candles})
+	service := &TradingSignalService{
+		marketData: registry,
+		symbols:    NewSymbolResolver(nil, true),
+		validator:  NewSignalValidator(2),
+		config:     &models.Config{},
+	}
+
+	result, err := service.runBacktest(context.Background(), models.BacktestRequest{
+		Symbols:       []string{"AAPL.US"},
+		Interval:      "1h",
+		Range:         "1d",
+		From:          start.Add(10 * time.Hour),
+		To:            start.Add(60 * time.Hour),
+		ScheduleTimes: []string{"10:30", "14:30"},
+		HoldingDays:   1,
+	}, &stubStrategy{t: t, candles: time.Hour})
+	if err != nil {
+		t.Fatalf("runBacktest returned error: %v", err)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatalf("runBacktest reported errors: %v", result.Errors)
+	}
+
+	// Runs at 10:30 and 14:30 on the first two days and 10:30 on the third
+	symbol := result.Symbols[0]
+	if symbol.Runs != 5 || symbol.BuySignals != 5 || symbol.Skipped != 0 {
+		t.Fatalf("unexpected run counts: %+v", symbol)
+	}
+	for _, trade := range result.Trades {
+		if trade.Status != OutcomeTargetHit {
+			t.Errorf("trade at %s = %s, want %s", trade.GeneratedAt, trade.Status, OutcomeTargetHit)
+		}
+	}
+	if result.Stats.Trades != 5 || result.Stats.WinRate != 100 {
+		t.Errorf("unexpected stats: %+v", result.Stats)
+	}
+}
+
+func TestComputeBacktestStats(t *testing.T) {
+	at := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	trade := func(hours int, returnPct float64) *models.SignalOutcome {
+		exit := at.Add(time.Duration(hours) * time.Hour)
+		return &models.SignalOutcome{EntryFilled: true, OutcomeAt: &exit, ReturnPct: returnPct}
+	}
+
+	stats := computeBacktestStats([]*models.SignalOutcome{
+		trade(3, 3),
+		trade(1, 5),
+		trade(2, -2),
+		{Status: OutcomeNotFilled},
+	})
+
+	near := func(got, want float64) bool { return math.Abs(got-want) < 1e-9 }
+	if stats.Signals != 4 || stats.Trades != 3 || stats.Wins != 2 || stats.Losses != 1 {
+		t.Fatalf("unexpected counts: %+v", stats)
+	}
+	if !near(stats.WinRate, 200.0/3) {
+		t.Errorf("win rate = %v, want %v", stats.WinRate, 200.0/3)
+	}
+	if !near(stats.ExpectancyPct, 2) {
+		t.Errorf("expectancy = %v, want 2", stats.ExpectancyPct)
+	}
+	if !near(stats.ProfitFactor, 4) {
+		t.Errorf("profit factor = %v, want 4", stats.ProfitFactor)
+	}
+	// Equity 1.05 then 1.029 in exit order
+	if !near(stats.MaxDrawdownPct, 2) {
+		t.Errorf("max drawdown = %v, want 2", stats.MaxDrawdownPct)
+	}
+	if want := 2 / math.Sqrt(13); !near(stats.SharpeRatio, want) {
+		t.Errorf("sharpe = %v, want %v", stats.SharpeRatio, want)
+	}
+	if want := (1.05*0.98*1.03 - 1) * 100; !near(stats.TotalReturnPct, want) {
+		t.Errorf("total return = %v, want %v", stats.TotalReturnPct, want)
+	}
+}
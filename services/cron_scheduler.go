@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -92,9 +93,13 @@ func (cs *CronScheduler) executeTradingSignal() {
 	log.Printf("🕐 [CRON] Executing scheduled trading signal generation at %s WIB", now.Format("2006-01-02 15:04:05"))
 
 	// Execute the trading signal generation
-	cs.tradingService.GenerateAllSignalsSummary()
+	job, started := cs.tradingService.GenerateAllSignalsSummary(context.Background(), "")
+	if !started {
+		log.Printf("⏭️ [CRON] Bulk analysis %s is already running, its summary will be sent instead", job.JobID)
+		return
+	}
 
-	log.Printf("✅ [CRON] Scheduled trading signal generation completed at %s WIB", time.Now().In(cs.timezone).Format("2006-01-02 15:04:05"))
+	log.Printf("✅ [CRON] Scheduled trading signal generation started as %s at %s WIB", job.JobID, time.Now().In(cs.timezone).Format("2006-01-02 15:04:05"))
 }
 
 // GetNextRuns returns the next scheduled execution times
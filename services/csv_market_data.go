@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// CSVMarketDataProvider reads candlestick data from CSV files on disk.
+// Files are looked up as <dir>/<SYMBOL>_<interval>.csv first and then <dir>/<SYMBOL>.csv,
+// using the full ticker (e.g. "BBCA.JK") before the bare code (e.g. "BBCA"),
+// with the header: timestamp,open,high,low,close,volume
+type CSVMarketDataProvider struct {
+	dir string
+}
+
+// NewCSVMarketDataProvider creates a new CSV market data provider
+func NewCSVMarketDataProvider(dir string) *CSVMarketDataProvider {
+	return &CSVMarketDataProvider{
+		dir: dir,
+	}
+}
+
+// Name returns the provider name
+func (c *CSVMarketDataProvider) Name() string {
+	return "csv"
+}
+
+// FetchCandles reads candles for a symbol and keeps only those inside the requested range
+func (c *CSVMarketDataProvider) FetchCandles(ctx context.Context, symbol, interval, dataRange string) ([]models.OHLCData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	path, err := c.findFile(symbol, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer file.Close()
+
+	ohlcData, err := parseOHLCCSV(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if len(ohlcData) == 0 {
+		return nil, fmt.Errorf("no valid OHLC data found for symbol: %s", symbol)
+	}
+
+	rangeDuration, err := parseRangeDuration(dataRange)
+	if err != nil {
+		return nil, err
+	}
+	if rangeDuration > 0 {
+		// Ranges are relative to the latest candle so recorded files keep working offline
+		cutoff := ohlcData[len(ohlcData)-1].Timestamp.Add(-rangeDuration)
+		start := 0
+		for start < len(ohlcData) && ohlcData[start].Timestamp.Before(cutoff) {
+			start++
+		}
+		ohlcData = ohlcData[start:]
+	}
+
+	return ohlcData, nil
+}
+
+// findFile locates the CSV file for a symbol and interval
+func (c *CSVMarketDataProvider) findFile(symbol, interval string) (string, error) {
+	names := []string{symbol}
+	if idx := strings.LastIndex(symbol, "."); idx != -1 {
+		names = append(names, symbol[:idx])
+	}
+
+	var candidates []string
+	for _, name := range names {
+		candidates = append(candidates,
+			filepath.Join(c.dir, fmt.Sprintf("%s_%s.csv", name, interval)),
+			filepath.Join(c.dir, name+".csv"))
+	}
+
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("no CSV file found for symbol %s in %s", symbol, c.dir)
+}
+
+// parseOHLCCSV parses CSV rows into OHLC data, accepting RFC3339 or unix timestamps
+func parseOHLCCSV(r io.Reader) ([]models.OHLCData, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	ohlcData := make([]models.OHLCData, 0, len(records))
+	for i, record := range records {
+		if len(record) < 6 {
+			return nil, fmt.Errorf("line %d: expected 6 columns, got %d", i+1, len(record))
+		}
+
+		// Skip header row
+		if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "timestamp") {
+			continue
+		}
+
+		timestamp, err := parseCSVTimestamp(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		var values [4]float64
+		for j := 0; j < 4; j++ {
+			values[j], err = strconv.ParseFloat(strings.TrimSpace(record[j+1]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid price: %w", i+1, err)
+			}
+		}
+
+		volume, err := strconv.ParseInt(strings.TrimSpace(record[5]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid volume: %w", i+1, err)
+		}
+
+		ohlcData = append(ohlcData, models.OHLCData{
+			Timestamp: timestamp,
+			Open:      values[0],
+			High:      values[1],
+			Low:       values[2],
+			Close:     values[3],
+			Volume:    volume,
+		})
+	}
+
+	return ohlcData, nil
+}
+
+// parseCSVTimestamp parses an RFC3339 or unix-seconds timestamp
+func parseCSVTimestamp(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp: %s", value)
+	}
+	return timestamp, nil
+}
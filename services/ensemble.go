@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// Ensemble aggregation methods
+const (
+	// EnsembleMajority gives every member one vote and averages the prices of the winning side
+	EnsembleMajority = "majority"
+	// EnsembleWeighted weights votes and prices by each member's confidence
+	EnsembleWeighted = "weighted"
+)
+
+// ensembleMember is a strategy run a number of times per ensemble signal
+type ensembleMember struct {
+	strategy Strategy
+	samples  int
+}
+
+// EnsembleStrategy runs several strategies, or several samples of a non-deterministic model,
+// and only emits BUY/SELL when enough of the vote agrees on the direction
+type EnsembleStrategy struct {
+	members      []ensembleMember
+	method       string
+	minAgreement float64
+}
+
+// NewEnsembleStrategy creates an ensemble from member specs such as "gemini:3" or
+// "ema_crossover", where the optional count is the number of samples per signal.
+// Members are looked up in the registry. minAgreement is in percent.
+func NewEnsembleStrategy(registry *StrategyRegistry, specs []string, method string, minAgreement float64) (*EnsembleStrategy, error) {
+	method = strings.ToLower(strings.TrimSpace(method))
+	if method == "" {
+		method = EnsembleMajority
+	}
+	if method != EnsembleMajority && method != EnsembleWeighted {
+		return nil, fmt.Errorf("invalid ensemble method %q (expected %s or %s)", method, EnsembleMajority, EnsembleWeighted)
+	}
+	if minAgreement <= 0 || minAgreement > 100 {
+		return nil, fmt.Errorf("ensemble minimum agreement must be between 0 and 100, got %.1f", minAgreement)
+	}
+
+	ensemble := &EnsembleStrategy{
+		method:       method,
+		minAgreement: minAgreement,
+	}
+	for _, spec := range specs {
+		name, count, hasCount := strings.Cut(strings.TrimSpace(spec), ":")
+		samples := 1
+		if hasCount {
+			var err error
+			if samples, err = strconv.Atoi(count); err != nil || samples < 1 {
+				return nil, fmt.Errorf("invalid ensemble member %q: the sample count must be a positive number", spec)
+			}
+		}
+		if strings.EqualFold(name, ensemble.Name()) {
+			return nil, fmt.Errorf("an ensemble cannot contain itself")
+		}
+
+		strategy, err := registry.Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ensemble member: %w", err)
+		}
+		ensemble.members = append(ensemble.members, ensembleMember{strategy: strategy, samples: samples})
+	}
+	if len(ensemble.members) == 0 {
+		return nil, fmt.Errorf("an ensemble needs at least one member")
+	}
+
+	return ensemble, nil
+}
+
+// Name returns the strategy name
+func (e *EnsembleStrategy) Name() string {
+	return "ensemble"
+}
+
+// GenerateTradingSignal runs every member and aggregates their signals. Failed members are
+// reported in the votes and left out of the tally; the signal fails only when all of them fail.
+func (e *EnsembleStrategy) GenerateTradingSignal(ctx context.Context, input *models.SignalInput) (*models.TradingSignal, error) {
+	result := &models.EnsembleResult{
+		Method:       e.method,
+		MinAgreement: e.minAgreement,
+	}
+
+	// voteSignals is parallel to the votes, with nil for failed members
+	var signals, voteSignals []*models.TradingSignal
+	var failures []string
+	for _, member := range e.members {
+		for i := 0; i < member.samples; i++ {
+			signal, err := member.strategy.GenerateTradingSignal(ctx, input)
+			if err != nil {
+				result.Votes = append(result.Votes, models.EnsembleVote{Strategy: member.strategy.Name(), Error: err.Error()})
+				voteSignals = append(voteSignals, nil)
+				failures = append(failures, fmt.Sprintf("%s: %v", member.strategy.Name(), err))
+				continue
+			}
+
+			signal.Signal = voteDirection(signal)
+			signals = append(signals, signal)
+			voteSignals = append(voteSignals, signal)
+			result.Votes = append(result.Votes, models.EnsembleVote{
+				Strategy:    member.strategy.Name(),
+				Signal:      signal.Signal,
+				Confidence:  signal.Confidence,
+				BuyPrice:    signal.BuyPrice,
+				TargetPrice: signal.TargetPrice,
+				StopLoss:    signal.StopLoss,
+			})
+		}
+	}
+	if len(signals) == 0 {
+		return nil, fmt.Errorf("all %d ensemble members failed: %s", len(result.Votes), strings.Join(failures, "; "))
+	}
+
+	// Tally the votes; a tie for the lead is no consensus
+	weights := map[string]float64{}
+	var total float64
+	for _, signal := range signals {
+		weight := e.weight(signal)
+		weights[signal.Signal] += weight
+		total += weight
+	}
+	result.Consensus = "WAIT"
+	best, leaders := 0.0, 0
+	for _, direction := range []string{"BUY", "SELL", "WAIT"} {
+		switch {
+		case weights[direction] > best:
+			best, leaders = weights[direction], 1
+			result.Consensus = direction
+		case weights[direction] == best && best > 0:
+			leaders++
+		}
+	}
+	if leaders > 1 {
+		result.Consensus = "WAIT"
+	}
+	if total > 0 {
+		result.Agreement = weights[result.Consensus] / total * 100
+	}
+
+	signal := e.aggregate(signals, result.Consensus)
+	signal.Ensemble = result
+	signal.Model = e.Name()
+
+	summary := fmt.Sprintf("Ensemble (%s) %s with %.0f%% agreement across %d votes", e.method, result.Consensus, result.Agreement, len(signals))
+	if result.Consensus != "WAIT" && result.Agreement < e.minAgreement {
+		summary = fmt.Sprintf("No consensus: %s has %.0f%% agreement across %d votes, below the required %.0f%%",
+			result.Consensus, result.Agreement, len(signals), e.minAgreement)
+		signal.Signal = "WAIT"
+	} else if leaders > 1 {
+		summary = fmt.Sprintf("No consensus: the %d votes are tied", len(signals))
+	}
+
+	var reasons strings.Builder
+	reasons.WriteString(summary)
+	for i, vote := range result.Votes {
+		if vote.Error != "" {
+			reasons.WriteString(fmt.Sprintf("\n- %s: failed (%s)", vote.Strategy, vote.Error))
+			continue
+		}
+		reasons.WriteString(fmt.Sprintf("\n- %s: %s %d%%", vote.Strategy, vote.Signal, vote.Confidence))
+		if reason := shortReason(voteSignals[i].Reason); reason != "" {
+			reasons.WriteString(" - " + reason)
+		}
+	}
+	signal.Reason = reasons.String()
+
+	annotateSignal(signal, input)
+	return signal, nil
+}
+
+// weight returns the vote weight of a member signal
+func (e *EnsembleStrategy) weight(signal *models.TradingSignal) float64 {
+	if e.method == EnsembleWeighted {
+		// A member with no confidence still counts a little so it is not silently ignored
+		return math.Max(float64(signal.Confidence), 1)
+	}
+	return 1
+}
+
+// aggregate averages the prices and confidence of the member signals voting for the direction
+func (e *EnsembleStrategy) aggregate(signals []*models.TradingSignal, direction string) *models.TradingSignal {
+	aggregated := &models.TradingSignal{Signal: direction}
+
+	var weightSum, confidenceSum float64
+	var count int
+	for _, signal := range signals {
+		if signal.Signal != direction {
+			continue
+		}
+		weight := e.weight(signal)
+		weightSum += weight
+		aggregated.BuyPrice += signal.BuyPrice * weight
+		aggregated.TargetPrice += signal.TargetPrice * weight
+		aggregated.StopLoss += signal.StopLoss * weight
+		confidenceSum += float64(signal.Confidence)
+		count++
+
+		if aggregated.OHLCVAnalysis == nil {
+			aggregated.OHLCVAnalysis = signal.OHLCVAnalysis
+		}
+		if aggregated.PromptHash == "" {
+			aggregated.PromptHash = signal.PromptHash
+		}
+	}
+	if weightSum > 0 {
+		aggregated.BuyPrice /= weightSum
+		aggregated.TargetPrice /= weightSum
+		aggregated.StopLoss /= weightSum
+	}
+	if count > 0 {
+		aggregated.Confidence = int(math.Round(confidenceSum / float64(count)))
+	}
+	return aggregated
+}
+
+// voteDirection normalizes a member signal to BUY, SELL or WAIT
+func voteDirection(signal *models.TradingSignal) string {
+	direction := strings.ToUpper(signal.Signal)
+	if direction != "BUY" && direction != "SELL" {
+		return "WAIT"
+	}
+	return direction
+}
+
+// maxMemberReasonLength caps each member's reason in the ensemble reason
+const maxMemberReasonLength = 200
+
+// shortReason collapses a member's reason onto one line and caps its length
+func shortReason(reason string) string {
+	reason = strings.Join(strings.Fields(reason), " ")
+	if runes := []rune(reason); len(runes) > maxMemberReasonLength {
+		reason = string(runes[:maxMemberReasonLength]) + "..."
+	}
+	return reason
+}
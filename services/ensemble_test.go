@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// fixedStrategy returns the same signal, or error, on every call
+type fixedStrategy struct {
+	name   string
+	signal models.TradingSignal
+	err    error
+}
+
+func (f *fixedStrategy) Name() string { return f.name }
+
+func (f *fixedStrategy) GenerateTradingSignal(ctx context.Context, input *models.SignalInput) (*models.TradingSignal, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	signal := f.signal
+	return &signal, nil
+}
+
+func TestEnsembleStrategy(t *testing.T) {
+	buyLow := &fixedStrategy{name: "buy_low", signal: models.TradingSignal{Signal: "BUY", BuyPrice: 100, TargetPrice: 110, StopLoss: 95, Confidence: 90}}
+	buyHigh := &fixedStrategy{name: "buy_high", signal: models.TradingSignal{Signal: "BUY", BuyPrice: 110, TargetPrice: 120, StopLoss: 105, Confidence: 30}}
+	sell := &fixedStrategy{name: "sell", signal: models.TradingSignal{Signal: "SELL", BuyPrice: 100, TargetPrice: 90, StopLoss: 105, Confidence: 60}}
+	wait := &fixedStrategy{name: "wait", signal: models.TradingSignal{Signal: "wait", Confidence: 50}}
+	broken := &fixedStrategy{name: "broken", err: errors.New("quota exceeded")}
+
+	registry := NewStrategyRegistry("buy_low", nil)
+	for _, strategy := range []Strategy{buyLow, buyHigh, sell, wait, broken} {
+		registry.Register(strategy)
+	}
+
+	tests := []struct {
+		name         string
+		members      []string
+		method       string
+		minAgreement float64
+		signal       string
+		consensus    string
+		agreement    float64
+		buyPrice     float64
+		confidence   int
+	}{
+		{"majority averages the agreeing prices", []string{"buy_low", "buy_high", "sell"}, EnsembleMajority, 60, "BUY", "BUY", 200.0 / 3, 105, 60},
+		{"weighted favours confident members", []string{"buy_low", "buy_high", "sell"}, EnsembleWeighted, 60, "BUY", "BUY", 120.0 / 180 * 100, 102.5, 60},
+		{"samples multiply a member's votes", []string{"sell:3", "buy_low"}, EnsembleMajority, 70, "SELL", "SELL", 75, 100, 60},
+		{"below the threshold waits", []string{"buy_low", "buy_high", "sell"}, EnsembleMajority, 75, "WAIT", "BUY", 200.0 / 3, 105, 60},
+		{"a tie waits", []string{"buy_low", "sell"}, EnsembleMajority, 50, "WAIT", "WAIT", 0, 0, 0},
+		{"failed members do not vote", []string{"buy_low", "broken", "wait"}, EnsembleMajority, 50, "WAIT", "WAIT", 50, 0, 50},
+		{"wait votes count against a direction", []string{"buy_low", "wait", "wait"}, EnsembleMajority, 60, "WAIT", "WAIT", 200.0 / 3, 0, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ensemble, err := NewEnsembleStrategy(registry, tt.members, tt.method, tt.minAgreement)
+			if err != nil {
+				t.Fatalf("NewEnsembleStrategy returned error: %v", err)
+			}
+			signal, err := ensemble.GenerateTradingSignal(context.Background(), ruleInput("1h", nil))
+			if err != nil {
+				t.Fatalf("GenerateTradingSignal returned error: %v", err)
+			}
+
+			if signal.Signal != tt.signal || signal.Ensemble.Consensus != tt.consensus {
+				t.Fatalf("signal = %s (consensus %s), want %s (consensus %s): %s",
+					signal.Signal, signal.Ensemble.Consensus, tt.signal, tt.consensus, signal.Reason)
+			}
+			if diff := signal.Ensemble.Agreement - tt.agreement; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("agreement = %v, want %v", signal.Ensemble.Agreement, tt.agreement)
+			}
+			if diff := signal.BuyPrice - tt.buyPrice; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("buy price = %v, want %v", signal.BuyPrice, tt.buyPrice)
+			}
+			if signal.Confidence != tt.confidence {
+				t.Errorf("confidence = %d, want %d", signal.Confidence, tt.confidence)
+			}
+			if signal.Model != "ensemble" || signal.StockSymbol != "AAPL" {
+				t.Errorf("model = %q, symbol = %q, want ensemble and AAPL", signal.Model, signal.StockSymbol)
+			}
+		})
+	}
+
+	t.Run("fails when every member fails", func(t *testing.T) {
+		ensemble, err := NewEnsembleStrategy(registry, []string{"broken:2"}, EnsembleMajority, 60)
+		if err != nil {
+			t.Fatalf("NewEnsembleStrategy returned error: %v", err)
+		}
+		if _, err := ensemble.GenerateTradingSignal(context.Background(), ruleInput("1h", nil)); err == nil {
+			t.Error("GenerateTradingSignal succeeded without any member signal")
+		}
+	})
+
+	for _, members := range [][]string{{"unknown"}, {"buy_low:0"}, {"ensemble"}, nil} {
+		if _, err := NewEnsembleStrategy(registry, members, EnsembleMajority, 60); err == nil {
+			t.Errorf("NewEnsembleStrategy accepted members %v", members)
+		}
+	}
+	if _, err := NewEnsembleStrategy(registry, []string{"buy_low"}, "median", 60); err == nil {
+		t.Error("NewEnsembleStrategy accepted an unknown method")
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// HTTPMarketDataProvider fetches candlestick data from a generic HTTP endpoint.
+// The endpoint receives symbol, interval and range query parameters and must
+// respond with a JSON array of OHLC data points.
+type HTTPMarketDataProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPMarketDataProvider creates a new HTTP market data provider
+func NewHTTPMarketDataProvider(baseURL string) *HTTPMarketDataProvider {
+	return &HTTPMarketDataProvider{
+		baseURL: baseURL,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Name returns the provider name
+func (h *HTTPMarketDataProvider) Name() string {
+	return "http"
+}
+
+// FetchCandles fetches candles for a symbol from the HTTP endpoint
+func (h *HTTPMarketDataProvider) FetchCandles(ctx context.Context, symbol, interval, dataRange string) ([]models.OHLCData, error) {
+	params := url.Values{}
+	params.Add("symbol", symbol)
+	params.Add("interval", interval)
+	params.Add("range", dataRange)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", h.baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data from %s: %w", h.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("market data endpoint returned status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var ohlcData []models.OHLCData
+	if err := json.NewDecoder(resp.Body).Decode(&ohlcData); err != nil {
+		return nil, fmt.Errorf("failed to decode market data response: %w", err)
+	}
+
+	if len(ohlcData) == 0 {
+		return nil, fmt.Errorf("no valid OHLC data found for symbol: %s", symbol)
+	}
+
+	return ohlcData, nil
+}
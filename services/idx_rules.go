@@ -0,0 +1,218 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// ErrSignalRejected is returned when a generated signal cannot be traded on the exchange
+var ErrSignalRejected = errors.New("signal rejected")
+
+// IDXMinimumPrice is the lowest price allowed on the IDX regular board
+const IDXMinimumPrice = 50
+
+// Price limit modes for targets and stops outside the auto-rejection range
+const (
+	PriceLimitModeClamp  = "clamp"
+	PriceLimitModeReject = "reject"
+)
+
+// IDXAutoRejectPercentage returns the auto-rejection percentage for a previous close
+func IDXAutoRejectPercentage(previousClose float64) float64 {
+	switch {
+	case previousClose <= 200:
+		return 0.35
+	case previousClose <= 5000:
+		return 0.25
+	default:
+		return 0.20
+	}
+}
+
+// IDXPriceLimits calculates the auto-rejection upper (ARA) and lower (ARB) limits for a previous close
+func IDXPriceLimits(previousClose float64) *models.PriceLimits {
+	percentage := IDXAutoRejectPercentage(previousClose)
+
+	upper := RoundToIDXTick(previousClose*(1+percentage), false)
+	lower := RoundToIDXTick(previousClose*(1-percentage), true)
+	if lower < IDXMinimumPrice {
+		lower = IDXMinimumPrice
+	}
+
+	return &models.PriceLimits{
+		PreviousClose: previousClose,
+		Percentage:    percentage * 100,
+		UpperLimit:    upper,
+		LowerLimit:    lower,
+	}
+}
+
+// IDXTickSize returns the IDX price fraction (fraksi harga) for a price
+func IDXTickSize(price float64) float64 {
+	switch {
+	case price < 200:
+		return 1
+	case price < 500:
+		return 2
+	case price < 2000:
+		return 5
+	case price < 5000:
+		return 10
+	default:
+		return 25
+	}
+}
+
+// RoundToIDXTick snaps a price to a valid IDX tick, rounding up or down
+func RoundToIDXTick(price float64, up bool) float64 {
+	tick := IDXTickSize(price)
+	steps := price / tick
+	// Avoid floating point noise turning an on-tick price into the next tick
+	if math.Abs(steps-math.Round(steps)) < 1e-9 {
+		return math.Round(steps) * tick
+	}
+
+	if up {
+		return math.Ceil(steps) * tick
+	}
+	return math.Floor(steps) * tick
+}
+
+// applyIDXTickRules snaps the signal's prices to valid IDX ticks, rounding each price in the
+// direction that makes the risk-reward ratio worse (entry against the trader, target closer,
+// stop further away). It records the original prices when anything changed and returns an
+// error wrapping ErrSignalRejected when the prices cannot be traded.
+func applyIDXTickRules(signal *models.TradingSignal) error {
+	direction := strings.ToUpper(signal.Signal)
+	original := models.SignalPrices{
+		BuyPrice:    signal.BuyPrice,
+		TargetPrice: signal.TargetPrice,
+		StopLoss:    signal.StopLoss,
+	}
+
+	switch direction {
+	case "BUY", "SELL":
+		if signal.BuyPrice <= 0 || signal.TargetPrice <= 0 || signal.StopLoss <= 0 {
+			return fmt.Errorf("%w: %s signal has non-positive prices (buy %.2f, target %.2f, stop %.2f)",
+				ErrSignalRejected, direction, signal.BuyPrice, signal.TargetPrice, signal.StopLoss)
+		}
+	default:
+		// WAIT signals are informational; only snap the prices that are present
+		signal.BuyPrice = roundToNearestTick(signal.BuyPrice)
+		signal.TargetPrice = roundToNearestTick(signal.TargetPrice)
+		signal.StopLoss = roundToNearestTick(signal.StopLoss)
+		if recordAdjustment(signal, original) {
+			signal.Adjustments = append(signal.Adjustments, "prices rounded to IDX tick sizes")
+		}
+		return nil
+	}
+
+	isBuy := direction == "BUY"
+	signal.BuyPrice = RoundToIDXTick(signal.BuyPrice, isBuy)
+	signal.TargetPrice = RoundToIDXTick(signal.TargetPrice, !isBuy)
+	signal.StopLoss = RoundToIDXTick(signal.StopLoss, !isBuy)
+	if recordAdjustment(signal, original) {
+		signal.Adjustments = append(signal.Adjustments, "prices rounded to IDX tick sizes")
+	}
+
+	for _, price := range []float64{signal.BuyPrice, signal.TargetPrice, signal.StopLoss} {
+		if price < IDXMinimumPrice {
+			return fmt.Errorf("%w: price %.0f is below the IDX minimum of %d", ErrSignalRejected, price, IDXMinimumPrice)
+		}
+	}
+
+	if signal.TargetPrice == signal.BuyPrice || signal.StopLoss == signal.BuyPrice {
+		return fmt.Errorf("%w: target %.0f or stop %.0f collapses onto entry %.0f after tick rounding",
+			ErrSignalRejected, signal.TargetPrice, signal.StopLoss, signal.BuyPrice)
+	}
+
+	return nil
+}
+
+// applyIDXPriceLimits checks the signal against the day's ARA/ARB limits. Targets and stops
+// outside the range are clamped to the limit in clamp mode and rejected in reject mode;
+// an entry outside the range is always rejected because it cannot be filled today.
+// Rejections are returned as errors wrapping ErrSignalRejected so the caller can mark the
+// signal invalid.
+func applyIDXPriceLimits(signal *models.TradingSignal, limits *models.PriceLimits, mode string) error {
+	signal.PriceLimits = limits
+
+	direction := strings.ToUpper(signal.Signal)
+	if direction != "BUY" && direction != "SELL" {
+		return nil
+	}
+
+	if signal.BuyPrice > limits.UpperLimit || signal.BuyPrice < limits.LowerLimit {
+		return fmt.Errorf("%w: entry %.0f is outside today's tradable range %.0f-%.0f (previous close %.0f)",
+			ErrSignalRejected, signal.BuyPrice, limits.LowerLimit, limits.UpperLimit, limits.PreviousClose)
+	}
+
+	original := models.SignalPrices{
+		BuyPrice:    signal.BuyPrice,
+		TargetPrice: signal.TargetPrice,
+		StopLoss:    signal.StopLoss,
+	}
+
+	prices := []struct {
+		name  string
+		price *float64
+	}{
+		{"target", &signal.TargetPrice},
+		{"stop loss", &signal.StopLoss},
+	}
+	for _, p := range prices {
+		var limit float64
+		var limitName string
+		switch {
+		case *p.price > limits.UpperLimit:
+			limit, limitName = limits.UpperLimit, "ARA"
+		case *p.price < limits.LowerLimit:
+			limit, limitName = limits.LowerLimit, "ARB"
+		default:
+			continue
+		}
+
+		if mode == PriceLimitModeReject {
+			return fmt.Errorf("%w: %s %.0f is beyond %s %.0f (previous close %.0f)",
+				ErrSignalRejected, p.name, *p.price, limitName, limit, limits.PreviousClose)
+		}
+
+		signal.Adjustments = append(signal.Adjustments, fmt.Sprintf("%s %.0f clamped to %s %.0f", p.name, *p.price, limitName, limit))
+		*p.price = limit
+	}
+	recordAdjustment(signal, original)
+
+	if signal.TargetPrice == signal.BuyPrice || signal.StopLoss == signal.BuyPrice {
+		return fmt.Errorf("%w: target %.0f or stop %.0f collapses onto entry %.0f at the price limit",
+			ErrSignalRejected, signal.TargetPrice, signal.StopLoss, signal.BuyPrice)
+	}
+
+	return nil
+}
+
+// roundToNearestTick snaps a price to the nearest IDX tick when it is set
+func roundToNearestTick(price float64) float64 {
+	if price <= 0 {
+		return price
+	}
+	tick := IDXTickSize(price)
+	return math.Round(price/tick) * tick
+}
+
+// recordAdjustment flags the signal and keeps the first original prices when they changed.
+// It reports whether any price changed.
+func recordAdjustment(signal *models.TradingSignal, original models.SignalPrices) bool {
+	if signal.BuyPrice == original.BuyPrice && signal.TargetPrice == original.TargetPrice && signal.StopLoss == original.StopLoss {
+		return false
+	}
+
+	signal.PriceAdjusted = true
+	if signal.OriginalPrices == nil {
+		signal.OriginalPrices = &original
+	}
+	return true
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+func TestIDXTickSizeBandBoundaries(t *testing.T) {
+	tests := []struct {
+		price float64
+		want  float64
+	}{
+		{199, 1},
+		{200, 2},
+		{499, 2},
+		{500, 5},
+		{1995, 5},
+		{2000, 10},
+		{4990, 10},
+		{5000, 25},
+	}
+
+	for _, tt := range tests {
+		if got := IDXTickSize(tt.price); got != tt.want {
+			t.Errorf("IDXTickSize(%v) = %v, want %v", tt.price, got, tt.want)
+		}
+	}
+}
+
+func TestRoundToIDXTick(t *testing.T) {
+	tests := []struct {
+		price float64
+		up    bool
+		want  float64
+	}{
+		{199, true, 199},
+		{200, false, 200},
+		{201, true, 202},
+		{201, false, 200},
+		{499, true, 500},
+		{499, false, 498},
+		{1997, true, 2000},
+		{1997, false, 1995},
+		{1995, true, 1995},
+		{4995, true, 5000},
+		{4995, false, 4990},
+		{5010, true, 5025},
+		{5010, false, 5000},
+		{1005.0000000001, true, 1005},
+	}
+
+	for _, tt := range tests {
+		if got := RoundToIDXTick(tt.price, tt.up); got != tt.want {
+			t.Errorf("RoundToIDXTick(%v, %t) = %v, want %v", tt.price, tt.up, got, tt.want)
+		}
+	}
+}
+
+func TestIDXPriceLimits(t *testing.T) {
+	tests := []struct {
+		previousClose float64
+		percentage    float64
+		upper         float64
+		lower         float64
+	}{
+		{60, 35, 81, 50}, // ARB floored at the minimum price
+		{200, 35, 270, 130},
+		{1000, 25, 1250, 750},
+		{5000, 25, 6250, 3750},
+		{5025, 20, 6025, 4020}, // ARA rounded down, ARB rounded up
+	}
+
+	for _, tt := range tests {
+		limits := IDXPriceLimits(tt.previousClose)
+		if limits.Percentage != tt.percentage || limits.UpperLimit != tt.upper || limits.LowerLimit != tt.lower {
+			t.Errorf("IDXPriceLimits(%v) = %.0f%% %v-%v, want %.0f%% %v-%v", tt.previousClose,
+				limits.Percentage, limits.LowerLimit, limits.UpperLimit, tt.percentage, tt.lower, tt.upper)
+		}
+	}
+}
+
+func TestApplyIDXTickRulesRoundsAgainstTheTrader(t *testing.T) {
+	tests := []struct {
+		name   string
+		signal models.TradingSignal
+		want   models.SignalPrices
+	}{
+		{
+			name:   "buy",
+			signal: models.TradingSignal{Signal: "BUY", BuyPrice: 1002, TargetPrice: 1103, StopLoss: 952},
+			want:   models.SignalPrices{BuyPrice: 1005, TargetPrice: 1100, StopLoss: 950},
+		},
+		{
+			name:   "sell",
+			signal: models.TradingSignal{Signal: "SELL", BuyPrice: 1002, TargetPrice: 903, StopLoss: 1052},
+			want:   models.SignalPrices{BuyPrice: 1000, TargetPrice: 905, StopLoss: 1055},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signal := tt.signal
+			if err := applyIDXTickRules(&signal); err != nil {
+				t.Fatalf("applyIDXTickRules returned error: %v", err)
+			}
+			got := models.SignalPrices{BuyPrice: signal.BuyPrice, TargetPrice: signal.TargetPrice, StopLoss: signal.StopLoss}
+			if got != tt.want {
+				t.Errorf("prices = %+v, want %+v", got, tt.want)
+			}
+			if !signal.PriceAdjusted || signal.OriginalPrices == nil || signal.OriginalPrices.BuyPrice != tt.signal.BuyPrice {
+				t.Errorf("original prices not recorded: %+v", signal.OriginalPrices)
+			}
+		})
+	}
+}
+
+func TestApplyIDXTickRulesRejectsUntradeableSignals(t *testing.T) {
+	tests := []struct {
+		name   string
+		signal models.TradingSignal
+	}{
+		{"target collapses onto entry", models.TradingSignal{Signal: "BUY", BuyPrice: 1003, TargetPrice: 1006, StopLoss: 950}},
+		{"sell target collapses onto entry", models.TradingSignal{Signal: "SELL", BuyPrice: 1004, TargetPrice: 998, StopLoss: 1100}},
+		{"below minimum price", models.TradingSignal{Signal: "BUY", BuyPrice: 55, TargetPrice: 70, StopLoss: 45}},
+		{"non-positive price", models.TradingSignal{Signal: "BUY", BuyPrice: 1000, TargetPrice: 1100, StopLoss: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signal := tt.signal
+			if err := applyIDXTickRules(&signal); !errors.Is(err, ErrSignalRejected) {
+				t.Errorf("applyIDXTickRules error = %v, want ErrSignalRejected", err)
+			}
+		})
+	}
+}
+
+func TestApplyIDXPriceLimits(t *testing.T) {
+	limits := IDXPriceLimits(1000) // ARB 750, ARA 1250
+
+	t.Run("clamp", func(t *testing.T) {
+		signal := models.TradingSignal{Signal: "BUY", BuyPrice: 1100, TargetPrice: 1300, StopLoss: 1050}
+		if err := applyIDXPriceLimits(&signal, limits, PriceLimitModeClamp); err != nil {
+			t.Fatalf("applyIDXPriceLimits returned error: %v", err)
+		}
+		if signal.TargetPrice != 1250 || !signal.PriceAdjusted {
+			t.Errorf("target = %v (adjusted %t), want 1250 clamped to ARA", signal.TargetPrice, signal.PriceAdjusted)
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		signal := models.TradingSignal{Signal: "SELL", BuyPrice: 800, TargetPrice: 700, StopLoss: 850}
+		if err := applyIDXPriceLimits(&signal, limits, PriceLimitModeReject); !errors.Is(err, ErrSignalRejected) {
+			t.Errorf("applyIDXPriceLimits error = %v, want ErrSignalRejected", err)
+		}
+	})
+
+	t.Run("entry outside range", func(t *testing.T) {
+		signal := models.TradingSignal{Signal: "BUY", BuyPrice: 1300, TargetPrice: 1400, StopLoss: 1200}
+		if err := applyIDXPriceLimits(&signal, limits, PriceLimitModeClamp); !errors.Is(err, ErrSignalRejected) {
+			t.Errorf("applyIDXPriceLimits error = %v, want ErrSignalRejected", err)
+		}
+	})
+
+	t.Run("clamp collapses onto entry", func(t *testing.T) {
+		signal := models.TradingSignal{Signal: "BUY", BuyPrice: 1250, TargetPrice: 1300, StopLoss: 1200}
+		if err := applyIDXPriceLimits(&signal, limits, PriceLimitModeClamp); !errors.Is(err, ErrSignalRejected) {
+			t.Errorf("applyIDXPriceLimits error = %v, want ErrSignalRejected", err)
+		}
+	})
+}
@@ -0,0 +1,433 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// ErrJobNotFound is returned for an unknown or expired bulk analysis job
+var ErrJobNotFound = errors.New("job not found")
+
+// ErrJobFinished is returned when cancelling a job that is no longer running
+var ErrJobFinished = errors.New("job already finished")
+
+// Bulk analysis job statuses
+const (
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobCancelled = "cancelled"
+)
+
+// Bulk analysis modes
+const (
+	BulkModeSignals = "signals" // Push each signal as it is generated, then the summary
+	BulkModeSummary = "summary" // Only send the summary
+)
+
+// Per-symbol progress statuses of a bulk analysis job
+const (
+	SymbolPending = "pending"
+	SymbolRunning = "running"
+	SymbolDone    = "done"
+	SymbolFailed  = "failed"
+)
+
+// maxFinishedJobs is how many finished jobs are kept for polling; older ones are forgotten
+const maxFinishedJobs = 50
+
+// Job event types streamed to subscribers
+const (
+	EventJobStarted    = "job_started"
+	EventSymbolStage   = "symbol_stage"
+	EventSignal        = "signal"
+	EventSymbolFailed  = "symbol_failed"
+	EventJobCompleted  = "job_completed"
+	EventJobCancelled  = "job_cancelled"
+	EventStreamDropped = "stream_dropped" // Last event to a subscriber that fell too far behind
+	jobEventBufferSize = 64               // Events a subscriber may fall behind before it is dropped
+)
+
+// JobPolicy limits how many bulk analysis jobs run at once. A request beyond a limit attaches
+// to a running job instead of starting another.
+type JobPolicy struct {
+	MaxRunning int // Jobs running at once across all chats, at least 1
+	MaxPerChat int // Jobs started by one chat running at once, at least 1
+}
+
+// JobManager tracks bulk analysis jobs in memory: their per-symbol progress while running and
+// their summary once finished
+type JobManager struct {
+	jobs  map[string]*BulkJob
+	order []string // Job IDs, oldest first
+	next  uint64
+	mutex sync.Mutex
+}
+
+// BulkJob is a tracked bulk analysis. Its fields are guarded by the manager's mutex.
+type BulkJob struct {
+	manager     *JobManager
+	result      models.BulkSignalResult
+	cancel      context.CancelFunc
+	subscribers []chan models.JobEvent
+}
+
+// NewJobManager creates an empty job manager
+func NewJobManager() *JobManager {
+	return &JobManager{
+		jobs: make(map[string]*BulkJob),
+	}
+}
+
+// Acquire attaches the chat to a running job when the policy allows no new one, preferring
+// the chat's own job and then one of the same mode, or starts a job analyzing symbols that
+// cancel stops. It reports whether the job was started.
+func (m *JobManager) Acquire(chatID, mode string, symbols []string, policy JobPolicy, cancel context.CancelFunc) (*BulkJob, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var running, sameMode, own []*BulkJob
+	for _, id := range m.order {
+		job := m.jobs[id]
+		if job.result.Status != JobRunning {
+			continue
+		}
+		running = append(running, job)
+		if job.result.Mode == mode {
+			sameMode = append(sameMode, job)
+		}
+		if job.result.ChatIDs[0] == chatID {
+			own = append(own, job)
+		}
+	}
+
+	var attach *BulkJob
+	switch {
+	case len(own) >= max(1, policy.MaxPerChat):
+		attach = own[len(own)-1]
+	case len(running) >= max(1, policy.MaxRunning) && len(sameMode) > 0:
+		attach = sameMode[len(sameMode)-1]
+	case len(running) >= max(1, policy.MaxRunning):
+		attach = running[len(running)-1]
+	}
+	if attach != nil {
+		if !slices.Contains(attach.result.ChatIDs, chatID) {
+			attach.result.ChatIDs = append(attach.result.ChatIDs, chatID)
+		}
+		return attach, false
+	}
+	return m.start(chatID, mode, symbols, cancel), true
+}
+
+// Start registers a running job for the chat analyzing symbols; cancel stops it
+func (m *JobManager) Start(chatID, mode string, symbols []string, cancel context.CancelFunc) *BulkJob {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.start(chatID, mode, symbols, cancel)
+}
+
+// start registers a running job with the lock held
+func (m *JobManager) start(chatID, mode string, symbols []string, cancel context.CancelFunc) *BulkJob {
+	m.next++
+	job := &BulkJob{
+		manager: m,
+		cancel:  cancel,
+		result: models.BulkSignalResult{
+			JobID:     fmt.Sprintf("job_%d", m.next),
+			Mode:      mode,
+			ChatIDs:   []string{chatID},
+			Status:    JobRunning,
+			Message:   fmt.Sprintf("Analyzing %d stocks", len(symbols)),
+			Total:     len(symbols),
+			Progress:  make([]models.BulkSymbolProgress, len(symbols)),
+			StartedAt: time.Now(),
+		},
+	}
+	for i, symbol := range symbols {
+		job.result.Progress[i] = models.BulkSymbolProgress{Symbol: symbol, Status: SymbolPending}
+	}
+
+	m.jobs[job.result.JobID] = job
+	m.order = append(m.order, job.result.JobID)
+	return job
+}
+
+// Get returns a snapshot of a job
+func (m *JobManager) Get(id string) (*models.BulkSignalResult, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	job, exists := m.jobs[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	return job.snapshot(), nil
+}
+
+// List returns snapshots of the tracked jobs, newest first
+func (m *JobManager) List() []*models.BulkSignalResult {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	results := make([]*models.BulkSignalResult, 0, len(m.order))
+	for i := len(m.order) - 1; i >= 0; i-- {
+		results = append(results, m.jobs[m.order[i]].snapshot())
+	}
+	return results
+}
+
+// Subscribe returns a snapshot of a job and a channel of its later events, closed once the job
+// finishes or the subscriber falls too far behind, in which case its last event is
+// EventStreamDropped. The channel of a finished job is closed already. unsubscribe stops the events and must be called when the caller is done.
+func (m *JobManager) Subscribe(id string) (*models.BulkSignalResult, <-chan models.JobEvent, func(), error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	job, exists := m.jobs[id]
+	if !exists {
+		return nil, nil, nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+
+	// One slot beyond the buffer is kept for the stream_dropped event
+	events := make(chan models.JobEvent, jobEventBufferSize+1)
+	if job.result.Status != JobRunning {
+		close(events)
+		return job.snapshot(), events, func() {}, nil
+	}
+	job.subscribers = append(job.subscribers, events)
+
+	unsubscribe := func() {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		job.unsubscribe(events)
+	}
+	return job.snapshot(), events, unsubscribe, nil
+}
+
+// Cancel cancels a running job
+func (m *JobManager) Cancel(id string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	job, exists := m.jobs[id]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	if job.result.Status != JobRunning {
+		return fmt.Errorf("%w: %s is %s", ErrJobFinished, id, job.result.Status)
+	}
+	job.cancel()
+	return nil
+}
+
+// CancelForChat stops the chat's part in the running jobs it is attached to: a job shared with
+// other chats carries on without it, and a job left with no other chat is cancelled. The chat
+// stays attached to the jobs it cancels so that it receives their cancellation notice.
+func (m *JobManager) CancelForChat(chatID string) (cancelled, detached int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, job := range m.jobs {
+		if job.result.Status != JobRunning {
+			continue
+		}
+		i := slices.Index(job.result.ChatIDs, chatID)
+		switch {
+		case i < 0:
+		case len(job.result.ChatIDs) > 1:
+			job.result.ChatIDs = slices.Delete(job.result.ChatIDs, i, i+1)
+			detached++
+		default:
+			job.cancel()
+			cancelled++
+		}
+	}
+	return cancelled, detached
+}
+
+// CancelAll cancels every running job whatever chats it serves and returns how many were cancelled
+func (m *JobManager) CancelAll() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	cancelled := 0
+	for _, job := range m.jobs {
+		if job.result.Status == JobRunning {
+			job.cancel()
+			cancelled++
+		}
+	}
+	return cancelled
+}
+
+// Status returns a snapshot of the job
+func (j *BulkJob) Status() *models.BulkSignalResult {
+	j.manager.mutex.Lock()
+	defer j.manager.mutex.Unlock()
+	return j.snapshot()
+}
+
+// attached reports whether the chat is still attached to the job. Every chat is attached to an
+// untracked run.
+func (j *BulkJob) attached(chatID string) bool {
+	if j == nil {
+		return true
+	}
+	j.manager.mutex.Lock()
+	defer j.manager.mutex.Unlock()
+	return slices.Contains(j.result.ChatIDs, chatID)
+}
+
+// snapshot copies the job's result so that it can be read without the lock
+func (j *BulkJob) snapshot() *models.BulkSignalResult {
+	result := j.result
+	result.ChatIDs = append([]string(nil), j.result.ChatIDs...)
+	result.Progress = append([]models.BulkSymbolProgress(nil), j.result.Progress...)
+	return &result
+}
+
+// ID returns the job ID, or an empty string for an untracked run
+func (j *BulkJob) ID() string {
+	if j == nil {
+		return ""
+	}
+	return j.result.JobID
+}
+
+// publish sends an event to the job's subscribers with the lock held. A subscriber whose buffer
+// is full is sent EventStreamDropped in its reserved slot and dropped rather than stalling the
+// analysis.
+func (j *BulkJob) publish(event models.JobEvent) {
+	event.JobID = j.result.JobID
+	event.Time = time.Now()
+	for _, events := range slices.Clone(j.subscribers) {
+		if len(events) < jobEventBufferSize {
+			events <- event
+			continue
+		}
+		events <- models.JobEvent{
+			Type:  EventStreamDropped,
+			JobID: event.JobID,
+			Error: fmt.Sprintf("fell %d events behind; fetch the job to catch up", jobEventBufferSize),
+			Job:   j.snapshot(),
+			Time:  event.Time,
+		}
+		j.unsubscribe(events)
+	}
+}
+
+// unsubscribe removes and closes a subscriber's channel with the lock held
+func (j *BulkJob) unsubscribe(events chan models.JobEvent) {
+	if i := slices.Index(j.subscribers, events); i >= 0 {
+		j.subscribers = slices.Delete(j.subscribers, i, i+1)
+		close(events)
+	}
+}
+
+// started announces that the job began analyzing its symbols
+func (j *BulkJob) started() {
+	if j == nil {
+		return
+	}
+	j.manager.mutex.Lock()
+	defer j.manager.mutex.Unlock()
+
+	j.publish(models.JobEvent{Type: EventJobStarted, Job: j.snapshot()})
+}
+
+// symbolStarted marks the symbol at index i as being analyzed
+func (j *BulkJob) symbolStarted(i int) {
+	if j == nil {
+		return
+	}
+	j.manager.mutex.Lock()
+	defer j.manager.mutex.Unlock()
+
+	j.result.Progress[i].Status = SymbolRunning
+}
+
+// symbolStage records the stage of signal generation the symbol at index i has reached
+func (j *BulkJob) symbolStage(i int, stage string) {
+	if j == nil {
+		return
+	}
+	j.manager.mutex.Lock()
+	defer j.manager.mutex.Unlock()
+
+	progress := &j.result.Progress[i]
+	progress.Stage = stage
+	j.publish(models.JobEvent{Type: EventSymbolStage, Symbol: progress.Symbol, Stage: stage})
+}
+
+// symbolFinished records the signal generated for the symbol at index i, or why it failed
+func (j *BulkJob) symbolFinished(i int, signal *models.TradingSignal, err error) {
+	if j == nil {
+		return
+	}
+	j.manager.mutex.Lock()
+	defer j.manager.mutex.Unlock()
+
+	progress := &j.result.Progress[i]
+	if err != nil {
+		progress.Status = SymbolFailed
+		progress.Error = err.Error()
+		j.publish(models.JobEvent{Type: EventSymbolFailed, Symbol: progress.Symbol, Stage: progress.Stage, Error: progress.Error})
+	} else {
+		progress.Status = SymbolDone
+		progress.Signal = signal.Signal
+		progress.Confidence = signal.Confidence
+		// Subscribers get a copy, as the signal's dispatch is recorded on it afterwards
+		copied := *signal
+		j.publish(models.JobEvent{Type: EventSignal, Symbol: progress.Symbol, Signal: &copied})
+	}
+	j.result.Analyzed++
+}
+
+// finish marks the job completed with its summary, or cancelled when err is set, and forgets
+// the oldest finished jobs beyond the retention limit
+func (j *BulkJob) finish(summary *models.SignalSummary, err error) {
+	if j == nil {
+		return
+	}
+	m := j.manager
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	j.result.CompletedAt = &now
+	if err != nil {
+		j.result.Status = JobCancelled
+		j.result.Message = fmt.Sprintf("Cancelled after %d of %d stocks", j.result.Analyzed, j.result.Total)
+	} else {
+		j.result.Status = JobCompleted
+		j.result.Message = fmt.Sprintf("Analyzed %d stocks", j.result.Total)
+		j.result.Summary = summary
+	}
+
+	event := models.JobEvent{Type: EventJobCompleted, Job: j.snapshot()}
+	if err != nil {
+		event.Type = EventJobCancelled
+		event.Error = err.Error()
+	}
+	j.publish(event)
+	for _, events := range slices.Clone(j.subscribers) {
+		j.unsubscribe(events)
+	}
+
+	finished := 0
+	for i := len(m.order) - 1; i >= 0; i-- {
+		id := m.order[i]
+		if m.jobs[id].result.Status == JobRunning {
+			continue
+		}
+		if finished++; finished > maxFinishedJobs {
+			delete(m.jobs, id)
+			m.order = append(m.order[:i], m.order[i+1:]...)
+		}
+	}
+}
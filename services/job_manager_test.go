@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+func TestJobManager(t *testing.T) {
+	manager := NewJobManager()
+	ctx, cancel := context.WithCancel(context.Background())
+	job := manager.Start("chat", "summary", []string{"BBCA", "TLKM"}, cancel)
+
+	job.symbolStarted(0)
+	job.symbolFinished(0, &models.TradingSignal{Signal: "BUY", Confidence: 80}, nil)
+	job.symbolStarted(1)
+
+	status, err := manager.Get(job.ID())
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if status.Status != JobRunning || status.Analyzed != 1 || status.Progress[0].Signal != "BUY" || status.Progress[1].Status != SymbolRunning {
+		t.Errorf("running job = %+v", status)
+	}
+
+	if err := manager.Cancel(job.ID()); err != nil || ctx.Err() == nil {
+		t.Fatalf("Cancel returned %v and left the context %v", err, ctx.Err())
+	}
+	job.finish(&models.SignalSummary{TotalAnalyzed: 1}, ctx.Err())
+
+	status, _ = manager.Get(job.ID())
+	if status.Status != JobCancelled || status.Summary != nil || status.CompletedAt == nil {
+		t.Errorf("cancelled job = %+v", status)
+	}
+	if err := manager.Cancel(job.ID()); !errors.Is(err, ErrJobFinished) {
+		t.Errorf("cancelling a finished job returned %v", err)
+	}
+	if _, err := manager.Get("job_404"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Get of an unknown job returned %v", err)
+	}
+
+	// Only the newest finished jobs are kept; running ones are never forgotten
+	running := manager.Start("chat", "signals", nil, func() {})
+	for i := 0; i < maxFinishedJobs; i++ {
+		manager.Start("chat", "signals", nil, func() {}).finish(&models.SignalSummary{}, nil)
+	}
+	jobs := manager.List()
+	if len(jobs) != maxFinishedJobs+1 {
+		t.Fatalf("tracking %d jobs, want %d", len(jobs), maxFinishedJobs+1)
+	}
+	if _, err := manager.Get(job.ID()); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("oldest finished job was kept: %v", err)
+	}
+	if jobs[0].JobID != fmt.Sprintf("job_%d", maxFinishedJobs+2) || jobs[len(jobs)-1].JobID != running.ID() {
+		t.Errorf("jobs listed from %s to %s, want newest first", jobs[0].JobID, jobs[len(jobs)-1].JobID)
+	}
+	if manager.CancelAll() != 1 {
+		t.Error("CancelAll did not cancel exactly the running job")
+	}
+}
+
+func TestJobManagerAcquire(t *testing.T) {
+	symbols := []string{"BBCA"}
+	tests := []struct {
+		name      string
+		policy    JobPolicy
+		chatID    string
+		mode      string
+		wantJob   string // Empty when a new job should start
+		wantChats []string
+	}{
+		{name: "attaches to the running job", policy: JobPolicy{MaxRunning: 1, MaxPerChat: 1}, chatID: "b", mode: BulkModeSignals, wantJob: "job_2", wantChats: []string{"a", "b"}},
+		{name: "prefers a job of the same mode", policy: JobPolicy{MaxRunning: 1, MaxPerChat: 1}, chatID: "b", mode: BulkModeSummary, wantJob: "job_1", wantChats: []string{"a", "b"}},
+		{name: "attaches a chat at its own limit", policy: JobPolicy{MaxRunning: 5, MaxPerChat: 2}, chatID: "a", mode: BulkModeSummary, wantJob: "job_2", wantChats: []string{"a"}},
+		{name: "starts a job below both limits", policy: JobPolicy{MaxRunning: 5, MaxPerChat: 1}, chatID: "b", mode: BulkModeSummary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewJobManager()
+			manager.Start("a", BulkModeSummary, symbols, func() {})
+			manager.Start("a", BulkModeSignals, symbols, func() {})
+
+			job, started := manager.Acquire(tt.chatID, tt.mode, symbols, tt.policy, func() {})
+			if tt.wantJob == "" {
+				if !started || job.ID() != "job_3" {
+					t.Fatalf("Acquire returned %s (started %v), want a new job", job.ID(), started)
+				}
+				return
+			}
+			if started || job.ID() != tt.wantJob {
+				t.Fatalf("Acquire returned %s (started %v), want to attach to %s", job.ID(), started, tt.wantJob)
+			}
+			if chats := job.Status().ChatIDs; fmt.Sprint(chats) != fmt.Sprint(tt.wantChats) {
+				t.Errorf("chats = %v, want %v", chats, tt.wantChats)
+			}
+		})
+	}
+}
+
+func TestJobManagerCancelForChat(t *testing.T) {
+	manager := NewJobManager()
+	policy := JobPolicy{MaxRunning: 5, MaxPerChat: 1}
+	sharedCtx, cancelShared := context.WithCancel(context.Background())
+	ownCtx, cancelOwn := context.WithCancel(context.Background())
+	otherCtx, cancelOther := context.WithCancel(context.Background())
+
+	// a started the shared job and b joined it; a also runs a job of its own, c one of its own
+	shared := manager.Start("a", BulkModeSignals, []string{"BBCA"}, cancelShared)
+	manager.Acquire("b", BulkModeSignals, nil, JobPolicy{MaxRunning: 1, MaxPerChat: 1}, func() {})
+	own, _ := manager.Acquire("a", BulkModeSummary, []string{"BBCA"}, JobPolicy{MaxRunning: 5, MaxPerChat: 2}, cancelOwn)
+	manager.Acquire("c", BulkModeSummary, []string{"BBCA"}, policy, cancelOther)
+
+	cancelled, detached := manager.CancelForChat("a")
+	if cancelled != 1 || detached != 1 {
+		t.Fatalf("CancelForChat cancelled %d and detached %d jobs, want 1 and 1", cancelled, detached)
+	}
+	if sharedCtx.Err() != nil || otherCtx.Err() != nil {
+		t.Error("CancelForChat cancelled a job other chats are attached to")
+	}
+	if ownCtx.Err() == nil {
+		t.Error("CancelForChat left the chat's own job running")
+	}
+	if chats := shared.Status().ChatIDs; !slices.Equal(chats, []string{"b"}) || shared.attached("a") {
+		t.Errorf("shared job chats = %v, want [b]", chats)
+	}
+	if chats := own.Status().ChatIDs; !slices.Equal(chats, []string{"a"}) {
+		t.Errorf("cancelled job chats = %v, want [a] to receive the cancellation notice", chats)
+	}
+
+	// b is now alone on the shared job, so its /cancel stops it
+	if cancelled, detached := manager.CancelForChat("b"); cancelled != 1 || detached != 0 || sharedCtx.Err() == nil {
+		t.Errorf("CancelForChat of the last chat cancelled %d and detached %d jobs", cancelled, detached)
+	}
+	if cancelled, detached := manager.CancelForChat("d"); cancelled != 0 || detached != 0 {
+		t.Errorf("CancelForChat of an unattached chat cancelled %d and detached %d jobs", cancelled, detached)
+	}
+}
+
+func TestJobManagerSubscribe(t *testing.T) {
+	manager := NewJobManager()
+	job := manager.Start("chat", BulkModeSummary, []string{"BBCA", "TLKM"}, func() {})
+
+	snapshot, events, unsubscribe, err := manager.Subscribe(job.ID())
+	if err != nil {
+		t.Fatalf("Subscribe returned error: %v", err)
+	}
+	defer unsubscribe()
+	if snapshot.Status != JobRunning || len(snapshot.Progress) != 2 {
+		t.Errorf("snapshot = %+v", snapshot)
+	}
+
+	job.started()
+	reportStage(withStageObserver(context.Background(), func(stage string) { job.symbolStage(0, stage) }), StageStrategy)
+	job.symbolFinished(0, &models.TradingSignal{Signal: "BUY"}, nil)
+	job.symbolFinished(1, nil, errors.New("no candles"))
+	job.finish(&models.SignalSummary{TotalAnalyzed: 1}, nil)
+
+	var got []models.JobEvent
+	for event := range events {
+		got = append(got, event)
+	}
+	want := []string{EventJobStarted, EventSymbolStage, EventSignal, EventSymbolFailed, EventJobCompleted}
+	if len(got) != len(want) {
+		t.Fatalf("received %d events, want %d: %+v", len(got), len(want), got)
+	}
+	for i, event := range got {
+		if event.Type != want[i] || event.JobID != job.ID() {
+			t.Errorf("event %d = %s of %s, want %s", i, event.Type, event.JobID, want[i])
+		}
+	}
+	if got[1].Stage != StageStrategy || got[2].Signal == nil || got[3].Error != "no candles" || got[4].Job.Summary == nil {
+		t.Errorf("events carry the wrong details: %+v", got)
+	}
+
+	// A finished job's stream is already closed
+	_, events, _, err = manager.Subscribe(job.ID())
+	if err != nil {
+		t.Fatalf("Subscribe to a finished job returned error: %v", err)
+	}
+	if _, ok := <-events; ok {
+		t.Error("stream of a finished job is open")
+	}
+}
+
+func TestJobManagerSubscribeDropsSlowSubscriber(t *testing.T) {
+	manager := NewJobManager()
+	job := manager.Start("chat", BulkModeSummary, []string{"BBCA"}, func() {})
+	_, events, unsubscribe, err := manager.Subscribe(job.ID())
+	if err != nil {
+		t.Fatalf("Subscribe returned error: %v", err)
+	}
+	defer unsubscribe()
+
+	for i := 0; i <= jobEventBufferSize; i++ {
+		job.symbolStage(0, StageStrategy)
+	}
+
+	var got []models.JobEvent
+	for event := range events {
+		got = append(got, event)
+	}
+	if len(got) != jobEventBufferSize+1 {
+		t.Fatalf("received %d events, want %d", len(got), jobEventBufferSize+1)
+	}
+	last := got[len(got)-1]
+	if got[len(got)-2].Type != EventSymbolStage || last.Type != EventStreamDropped || last.Job == nil || last.Error == "" {
+		t.Errorf("slow subscriber ended with %+v, want a stream_dropped event with the job", last)
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"sync"
+)
+
+// LLMProvider is a language model backend that completes prompts. Each provider is
+// registered as a strategy under its name, so it can be selected like any other strategy.
+type LLMProvider interface {
+	// Name returns the name used to select the provider (e.g. "gemini", "openai")
+	Name() string
+	// Model returns the model name reported on generated signals
+	Model() string
+	// Generate returns the model's text response to a prompt
+	Generate(ctx context.Context, prompt string) (string, error)
+	// Close releases the provider's resources
+	Close() error
+}
+
+// JSONSchemaProvider is implemented by providers that can constrain a response to a JSON schema
+type JSONSchemaProvider interface {
+	// GenerateJSON returns the model's response to a prompt as JSON matching the schema
+	GenerateJSON(ctx context.Context, prompt string, schema map[string]any) (string, error)
+}
+
+// FakeLLMProvider is a deterministic provider for tests. It returns its responses in order,
+// repeating the last one, and records every prompt it receives.
+type FakeLLMProvider struct {
+	responses []string
+	prompts   []string
+	calls     int
+	mutex     sync.Mutex
+}
+
+// NewFakeLLMProvider creates a fake provider returning the given responses
+func NewFakeLLMProvider(responses ...string) *FakeLLMProvider {
+	return &FakeLLMProvider{responses: responses}
+}
+
+// Name returns the provider name
+func (f *FakeLLMProvider) Name() string {
+	return "fake"
+}
+
+// Model returns the model name
+func (f *FakeLLMProvider) Model() string {
+	return "fake"
+}
+
+// Generate records the prompt and returns the next canned response
+func (f *FakeLLMProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.prompts = append(f.prompts, prompt)
+	if len(f.responses) == 0 {
+		return "", nil
+	}
+	response := f.responses[min(f.calls, len(f.responses)-1)]
+	f.calls++
+	return response, nil
+}
+
+// Prompts returns the prompts received so far
+func (f *FakeLLMProvider) Prompts() []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]string(nil), f.prompts...)
+}
+
+// Close does nothing
+func (f *FakeLLMProvider) Close() error {
+	return nil
+}
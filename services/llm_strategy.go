@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// MaxPromptCandles is the maximum number of most recent candles listed in the prompt.
+// Indicators, patterns and levels are still computed from every fetched candle.
+const MaxPromptCandles = 150
+
+// DefaultLLMRepairAttempts is how many times a malformed model response is sent back for repair
+// when LLM_REPAIR_ATTEMPTS is not set
+const DefaultLLMRepairAttempts = 2
+
+// LLMStrategy generates trading signals by prompting a language model with the analysis inputs
+type LLMStrategy struct {
+	provider       LLMProvider
+	repairAttempts int
+}
+
+// NewLLMStrategy creates a strategy backed by an LLM provider; it is named after the provider.
+// Responses that do not match the signal schema are sent back for repair up to repairAttempts times.
+func NewLLMStrategy(provider LLMProvider, repairAttempts int) *LLMStrategy {
+	if repairAttempts < 0 {
+		repairAttempts = 0
+	}
+	return &LLMStrategy{provider: provider, repairAttempts: repairAttempts}
+}
+
+// Name returns the strategy name
+func (s *LLMStrategy) Name() string {
+	return s.provider.Name()
+}
+
+// GenerateTradingSignal generates a trading signal using the language model
+func (s *LLMStrategy) GenerateTradingSignal(ctx context.Context, input *models.SignalInput) (*models.TradingSignal, error) {
+	prompt := s.buildPrompt(input)
+
+	request := prompt
+	for attempt := 0; ; attempt++ {
+		text, err := s.generate(ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate content: %w", err)
+		}
+
+		signal, err := parseSignalResponse(text)
+		if err != nil {
+			var schemaErr *SignalSchemaError
+			if !errors.As(err, &schemaErr) || attempt >= s.repairAttempts {
+				return nil, fmt.Errorf("failed to parse signal response after %d attempts: %w", attempt+1, err)
+			}
+			log.Printf("%s response for %s did not match the signal schema, requesting a repair (%d/%d): %v",
+				s.provider.Name(), input.Symbol.Ticker, attempt+1, s.repairAttempts, err)
+			request = repairPrompt(prompt, text, schemaErr)
+			continue
+		}
+
+		annotateSignal(signal, input)
+		signal.Model = s.provider.Model()
+		signal.PromptHash = hashPrompt(prompt)
+		return signal, nil
+	}
+}
+
+// generate sends a prompt to the provider, constraining the response to the signal schema
+// when the provider supports it
+func (s *LLMStrategy) generate(ctx context.Context, prompt string) (string, error) {
+	if structured, ok := s.provider.(JSONSchemaProvider); ok {
+		return structured.GenerateJSON(ctx, prompt, signalResponseSchema)
+	}
+	return s.provider.Generate(ctx, prompt)
+}
+
+// repairPrompt asks the model to fix a response that did not match the signal schema
+func repairPrompt(prompt, response string, schemaErr *SignalSchemaError) string {
+	var builder strings.Builder
+	builder.WriteString(prompt)
+	builder.WriteString("\n\n### Perbaikan Format\nJawaban kamu sebelumnya tidak sesuai skema JSON:\n")
+	for _, problem := range schemaErr.Problems {
+		builder.WriteString("- " + problem + "\n")
+	}
+	builder.WriteString("\nJawaban sebelumnya:\n")
+	builder.WriteString(response)
+	builder.WriteString("\n\nKirim ulang analisa yang sama sebagai satu objek JSON yang valid sesuai skema, tanpa teks lain.")
+	return builder.String()
+}
+
+// buildPrompt creates the prompt for the language model
+func (s *LLMStrategy) buildPrompt(input *models.SignalInput) string {
+	ohlcData := input.OHLCData
+	if len(ohlcData) > MaxPromptCandles {
+		ohlcData = ohlcData[len(ohlcData)-MaxPromptCandles:]
+	}
+	timeframe := describeInterval(input.Interval)
+	exchange, _ := LookupExchange(input.Symbol.Exchange)
+
+	var dataBuilder strings.Builder
+	dataBuilder.WriteString(fmt.Sprintf("Saya ingin kamu menganalisa saham %s yang diperdagangkan di %s. Data di bawah ini adalah %d candlestick %s dengan range %s (dari %s sampai %s):\n\n",
+		input.Symbol.Code,
+		exchange.Name,
+		len(ohlcData),
+		timeframe,
+		describeRange(input.Range),
+		ohlcData[0].Timestamp.Format("2006-01-02 15:04"),
+		ohlcData[len(ohlcData)-1].Timestamp.Format("2006-01-02 15:04")))
+
+	dataBuilder.WriteString("candlestick_data = [\n")
+	for _, data := range ohlcData {
+		dataBuilder.WriteString(fmt.Sprintf("{\n  \"timestamp\": \"%s\",\n  \"open\": %.2f,\n  \"high\": %.2f,\n  \"low\": %.2f,\n  \"close\": %.2f,\n  \"volume\": %d\n},\n",
+			data.Timestamp.Format("2006-01-02T15:04:05-07:00"),
+			data.Open, data.High, data.Low, data.Close, data.Volume))
+	}
+	dataBuilder.WriteString("]\n\n")
+
+	if input.Indicators != nil {
+		dataBuilder.WriteString(formatIndicators(input.Indicators))
+	}
+
+	dataBuilder.WriteString(formatPatterns(input.Patterns))
+
+	if input.Levels != nil {
+		dataBuilder.WriteString(formatLevels(input.Levels))
+	}
+
+	if input.PriceLimits != nil {
+		dataBuilder.WriteString(fmt.Sprintf("batas_harga_hari_ini = {\"previous_close\": %.0f, \"ara\": %.0f, \"arb\": %.0f}\n\n",
+			input.PriceLimits.PreviousClose, input.PriceLimits.UpperLimit, input.PriceLimits.LowerLimit))
+	}
+
+	prompt := fmt.Sprintf(`%s
+
+### Instruksi:
+Lakukan analisa teknikal berdasarkan data candlestick yang diberikan.
+Gunakan nilai indikator teknikal yang sudah dihitung di atas apa adanya, jangan menghitung ulang indikator tersebut dari data candlestick.
+
+%s
+%s
+Berikan sinyal trading:
+- Sinyal: "BUY", "SELL", atau "WAIT"
+- Harga Beli Ideal
+- Target Jual (harus memenuhi risk-reward ratio minimal di atas)
+- Stop Loss
+- Confidence Level (0-100%%)
+- Gunakan analisis teknikal untuk mendeteksi:
+	- Pola candlestick (gunakan pola_candlestick yang sudah terdeteksi di atas)
+	- Crossover antara 5EMA dan 20EMA (lihat ema_5_20_crossover)
+	- Level support dan resistance (gunakan level_harga yang sudah dihitung di atas)
+	- RSI dan MACD (gunakan rsi_14, macd, macd_signal dan macd_histogram di atas)
+	- Posisi harga terhadap VWAP dan Bollinger Bands, serta ATR untuk jarak stop loss
+	- Breakout harga dengan volume tinggi
+- Jelaskan alasan di balik sinyal tersebut (berdasarkan analisa teknikal)
+
+**Tambahan: Analisa OHLCV Terkini**
+- Open: Harga pembukaan sesi 1/2
+- High: Harga tertinggi terakhir
+- Low: Harga terendah terakhir
+- Close: Harga penutupan terakhir
+- Volume: Volume perdagangan terakhir
+- Penjelasan OHLCV:
+  Tulis penjelasan dalam format naratif. Contoh:
+  "Harga pembukaan sesi pertama berada di [OpenSesi1], sementara sesi kedua dibuka di [OpenSesi2]. Sepanjang hari, harga mencapai titik tertinggi di [High] dan terendah di [Low]. Saham ditutup di harga [Close] dengan total volume perdagangan sebesar [Volume]. Pola pergerakan harga menunjukkan [...analisa teknikal seperti bullish/bearish/momentum volume...]."
+
+Berikan output dalam format JSON:
+{
+  "signal": "BUY",
+  "buy_price": 2750,
+  "target_price": 2850,
+  "stop_loss": 2725,
+  "confidence": 82,
+  "reason": "Terjadi pola bullish engulfing pada timeframe %s. Risk-reward ratio 1:2 terpenuhi (risk: 25, reward: 100).",
+  "ohlcv_analysis": {
+    "open": 2740,
+    "high": 2750,
+    "low": 2730,
+    "close": 2745,
+    "volume": 80000,
+    "explanation": "Harga pembukaan sesi pertama berada di [OpenSesi1], sementara sesi kedua dibuka di [OpenSesi2]. Sepanjang hari, harga mencapai titik tertinggi di [High] dan terendah di [Low]. Saham ditutup di harga [Close] dengan total volume perdagangan sebesar [Volume]. Pola pergerakan harga menunjukkan [...analisa teknikal seperti bullish/bearish/momentum volume...]."
+  }
+}
+
+Output harus berupa satu objek JSON saja, tanpa teks lain, dan mengikuti skema berikut. Nilai "signal" harus persis "BUY", "SELL" atau "WAIT" (huruf besar), semua field wajib diisi dan confidence berupa bilangan bulat 0-100:
+%s`, dataBuilder.String(), riskRewardRules(input.MinRiskReward), exchangePriceRules(input.Symbol), timeframe, signalSchemaJSON())
+
+	return prompt
+}
+
+// hashPrompt returns the hex SHA-256 of a prompt so stored signals can be matched to the prompt version
+func hashPrompt(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+// riskRewardRules returns prompt instructions for the minimum risk-reward ratio enforced by the validator
+func riskRewardRules(minRatio float64) string {
+	return fmt.Sprintf(`**PENTING: Risk-Reward Ratio 1:%[1]g**
+- Setiap sinyal trading HARUS memiliki risk-reward ratio minimal 1:%[1]g
+- Jika sinyal = "BUY": Stop Loss < Buy Price < Target Price, dan Target Price harus minimal %[1]gx jarak dari Buy Price ke Stop Loss
+- Jika sinyal = "SELL": Target Price < Sell Price < Stop Loss, dan Target Price harus minimal %[1]gx jarak dari Sell Price ke Stop Loss
+- Contoh: Buy Price = 1000, Stop Loss = 950 (risk = 50), maka Target Price minimal = %[2]g (reward = %[3]g)
+- Sinyal BUY/SELL yang tidak memenuhi aturan ini akan otomatis diubah menjadi "WAIT" oleh sistem, jadi berikan "WAIT" jika rasio tidak bisa dipenuhi
+`, minRatio, 1000+50*minRatio, 50*minRatio)
+}
+
+// exchangePriceRules returns prompt instructions about valid price increments on the symbol's exchange
+func exchangePriceRules(symbol models.Symbol) string {
+	if symbol.Exchange != "IDX" {
+		return ""
+	}
+	return `**Fraksi Harga BEI**
+- Semua harga (beli, target, stop loss) harus mengikuti fraksi harga: < 200 kelipatan 1, 200-500 kelipatan 2, 500-2.000 kelipatan 5, 2.000-5.000 kelipatan 10, >= 5.000 kelipatan 25
+- Harga minimum adalah 50
+- Harga beli, target dan stop loss harus berada di antara ARB dan ARA pada batas_harga_hari_ini (jika tersedia)
+`
+}
+
+// formatIndicators renders the computed indicators as a prompt section, skipping values that are unavailable
+func formatIndicators(ind *models.TechnicalIndicators) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("indikator_teknikal (dihitung dari %d candle terakhir, nilai pada candle terakhir) = {\n", ind.CandleCount))
+
+	values := []struct {
+		name  string
+		value *float64
+	}{
+		{"ema_5", ind.EMA5},
+		{"ema_20", ind.EMA20},
+		{"sma_20", ind.SMA20},
+		{"rsi_14", ind.RSI14},
+		{"macd", ind.MACD},
+		{"macd_signal", ind.MACDSignal},
+		{"macd_histogram", ind.MACDHistogram},
+		{"bollinger_upper", ind.BollingerUpper},
+		{"bollinger_middle", ind.BollingerMid},
+		{"bollinger_lower", ind.BollingerLower},
+		{"atr_14", ind.ATR14},
+		{"vwap", ind.VWAP},
+		{"obv", ind.OBV},
+	}
+	for _, v := range values {
+		if v.value == nil {
+			builder.WriteString(fmt.Sprintf("  \"%s\": \"tidak tersedia (data kurang)\",\n", v.name))
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("  \"%s\": %.4f,\n", v.name, *v.value))
+	}
+
+	if ind.EMACrossover != "" {
+		builder.WriteString(fmt.Sprintf("  \"ema_5_20_crossover\": \"%s\",\n", ind.EMACrossover))
+	}
+	if ind.OBVTrend != "" {
+		builder.WriteString(fmt.Sprintf("  \"obv_trend\": \"%s\",\n", ind.OBVTrend))
+	}
+	builder.WriteString("}\n\n")
+
+	return builder.String()
+}
+
+// formatPatterns renders the detected candlestick patterns as a prompt section
+func formatPatterns(detected []models.CandlestickPattern) string {
+	if len(detected) == 0 {
+		return "pola_candlestick = [] (tidak ada pola yang terdeteksi pada candle terakhir)\n\n"
+	}
+
+	var builder strings.Builder
+	builder.WriteString("pola_candlestick = [\n")
+	for _, pattern := range detected {
+		builder.WriteString(fmt.Sprintf("  {\"pola\": \"%s\", \"arah\": \"%s\", \"timestamp\": \"%s\", \"kekuatan\": %d},\n",
+			pattern.Name, pattern.Direction, pattern.Timestamp.Format("2006-01-02T15:04:05-07:00"), pattern.Strength))
+	}
+	builder.WriteString("]\n\n")
+
+	return builder.String()
+}
+
+// formatLevels renders the computed support, resistance and pivot levels as a prompt section
+func formatLevels(keyLevels *models.KeyLevels) string {
+	var builder strings.Builder
+	builder.WriteString("level_harga = {\n")
+
+	if keyLevels.Classic != nil {
+		builder.WriteString(fmt.Sprintf("  \"sesi_sebelumnya\": {\"high\": %.2f, \"low\": %.2f, \"close\": %.2f},\n",
+			keyLevels.PreviousHigh, keyLevels.PreviousLow, keyLevels.PreviousClose))
+		pivots := []struct {
+			name   string
+			levels *models.PivotLevels
+		}{
+			{"pivot_klasik", keyLevels.Classic},
+			{"pivot_fibonacci", keyLevels.Fibonacci},
+			{"pivot_camarilla", keyLevels.Camarilla},
+		}
+		for _, p := range pivots {
+			builder.WriteString(fmt.Sprintf("  \"%s\": {\"pivot\": %.2f, \"r1\": %.2f, \"r2\": %.2f, \"r3\": %.2f, \"s1\": %.2f, \"s2\": %.2f, \"s3\": %.2f},\n",
+				p.name, p.levels.Pivot, p.levels.R1, p.levels.R2, p.levels.R3, p.levels.S1, p.levels.S2, p.levels.S3))
+		}
+	}
+
+	sides := []struct {
+		name   string
+		levels []models.PriceLevel
+	}{
+		{"support", keyLevels.Supports},
+		{"resistance", keyLevels.Resistances},
+	}
+	for _, side := range sides {
+		builder.WriteString(fmt.Sprintf("  \"%s\": [", side.name))
+		for i, level := range side.levels {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			builder.WriteString(fmt.Sprintf("{\"harga\": %.2f, \"sumber\": \"%s\", \"kekuatan\": %d}", level.Price, level.Source, level.Strength))
+		}
+		builder.WriteString("],\n")
+	}
+
+	if keyLevels.VolumeProfile != nil {
+		builder.WriteString(fmt.Sprintf("  \"volume_profile\": {\"poc\": %.2f, \"value_area_high\": %.2f, \"value_area_low\": %.2f},\n",
+			keyLevels.VolumeProfile.PointOfControl, keyLevels.VolumeProfile.ValueAreaHigh, keyLevels.VolumeProfile.ValueAreaLow))
+	}
+	builder.WriteString("}\n\n")
+
+	return builder.String()
+}
+
+// describeInterval returns the Indonesian description of a candle interval (e.g. "15m" -> "15-menit")
+func describeInterval(interval string) string {
+	switch {
+	case interval == "1d":
+		return "harian"
+	case strings.HasSuffix(interval, "m"):
+		return strings.TrimSuffix(interval, "m") + "-menit"
+	case strings.HasSuffix(interval, "h"):
+		return strings.TrimSuffix(interval, "h") + "-jam"
+	default:
+		return interval
+	}
+}
+
+// describeRange returns the Indonesian description of a lookback range (e.g. "5d" -> "5 hari")
+func describeRange(dataRange string) string {
+	units := []struct {
+		suffix string
+		label  string
+	}{
+		{"mo", "bulan"},
+		{"wk", "minggu"},
+		{"d", "hari"},
+		{"y", "tahun"},
+	}
+
+	for _, u := range units {
+		if strings.HasSuffix(dataRange, u.suffix) {
+			return strings.TrimSuffix(dataRange, u.suffix) + " " + u.label
+		}
+	}
+	return dataRange
+}
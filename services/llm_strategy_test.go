@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// llmInput builds a prompt input from a few rising candles
+func llmInput() *models.SignalInput {
+	start := time.Date(2025, 6, 12, 9, 0, 0, 0, time.UTC)
+	var candles []models.OHLCData
+	for i := 0; i < 5; i++ {
+		price := 1000 + 10*float64(i)
+		candles = append(candles, models.OHLCData{Timestamp: start.Add(time.Duration(i*5) * time.Minute), Open: price, High: price + 5, Low: price - 5, Close: price + 3, Volume: 1000})
+	}
+	return ruleInput("5m", candles)
+}
+
+// validSignalResponse is a model response that matches the signal schema
+const validSignalResponse = `{"signal": "BUY", "buy_price": 1045, "target_price": 1085, "stop_loss": 1025, "confidence": 75, "reason": "breakout",
+	"ohlcv_analysis": {"open": 1040, "high": 1045, "low": 1035, "close": 1043, "volume": 1000, "explanation": "rising"}}`
+
+func TestLLMStrategy(t *testing.T) {
+	provider := NewFakeLLMProvider("Here you go:\n" + validSignalResponse)
+	strategy := NewLLMStrategy(provider, 0)
+
+	signal, err := strategy.GenerateTradingSignal(context.Background(), llmInput())
+	if err != nil {
+		t.Fatalf("GenerateTradingSignal returned error: %v", err)
+	}
+	if signal.Signal != "BUY" || signal.BuyPrice != 1045 || signal.Confidence != 75 {
+		t.Errorf("signal = %+v, want BUY at 1045 with confidence 75", signal)
+	}
+	if strategy.Name() != "fake" || signal.Model != "fake" || signal.StockSymbol != "AAPL" {
+		t.Errorf("name = %q, model = %q, symbol = %q", strategy.Name(), signal.Model, signal.StockSymbol)
+	}
+
+	prompts := provider.Prompts()
+	if len(prompts) != 1 || signal.PromptHash != hashPrompt(prompts[0]) {
+		t.Errorf("prompt hash %q does not match the %d recorded prompts", signal.PromptHash, len(prompts))
+	}
+	if !strings.Contains(prompts[0], "candlestick_data") {
+		t.Error("prompt does not list the candles")
+	}
+
+	if _, err := NewLLMStrategy(NewFakeLLMProvider("no json here"), 0).GenerateTradingSignal(context.Background(), llmInput()); err == nil {
+		t.Error("GenerateTradingSignal accepted a response without JSON")
+	}
+
+	t.Run("repairs a malformed response", func(t *testing.T) {
+		malformed := strings.Replace(validSignalResponse, `"BUY"`, `"Buy"`, 1)
+		provider := NewFakeLLMProvider(malformed, validSignalResponse)
+
+		signal, err := NewLLMStrategy(provider, 1).GenerateTradingSignal(context.Background(), llmInput())
+		if err != nil {
+			t.Fatalf("GenerateTradingSignal returned error: %v", err)
+		}
+		prompts := provider.Prompts()
+		if len(prompts) != 2 || !strings.Contains(prompts[1], "Perbaikan Format") || !strings.Contains(prompts[1], `got "Buy"`) {
+			t.Fatalf("expected a repair prompt quoting the problem, got %d prompts", len(prompts))
+		}
+		if signal.Signal != "BUY" || signal.PromptHash != hashPrompt(prompts[0]) {
+			t.Errorf("signal = %s with prompt hash %q, want BUY hashed on the original prompt", signal.Signal, signal.PromptHash)
+		}
+
+		if _, err := NewLLMStrategy(NewFakeLLMProvider(malformed, validSignalResponse), 0).GenerateTradingSignal(context.Background(), llmInput()); err == nil {
+			t.Error("GenerateTradingSignal repaired a response without repair attempts")
+		}
+	})
+}
+
+func TestOpenAIProvider(t *testing.T) {
+	var request openAIChatRequest
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		authorization = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{\"signal\": \"WAIT\"}"}}]}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(server.URL+"/v1/", "secret", models.LLMSettings{Model: "llama3", Temperature: 0.2, TopK: 20}, false, nil)
+	text, err := provider.Generate(context.Background(), "analyse")
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if text != `{"signal": "WAIT"}` {
+		t.Errorf("text = %q", text)
+	}
+	if request.Model != "llama3" || request.Temperature != 0.2 || request.TopK != 20 || request.TopP != 0 {
+		t.Errorf("request = %+v, want llama3 with temperature 0.2 and top_k 20", request)
+	}
+	if len(request.Messages) != 1 || request.Messages[0].Content != "analyse" {
+		t.Errorf("messages = %+v", request.Messages)
+	}
+	if authorization != "Bearer secret" {
+		t.Errorf("authorization = %q", authorization)
+	}
+
+	if request.ResponseFormat != nil {
+		t.Errorf("Generate sent a response format: %+v", request.ResponseFormat)
+	}
+
+	if _, err := NewOpenAIProvider(server.URL+"/v1", "", models.LLMSettings{}, true, nil).GenerateJSON(context.Background(), "analyse", signalResponseSchema); err != nil {
+		t.Fatalf("GenerateJSON returned error: %v", err)
+	}
+	if request.ResponseFormat == nil || request.ResponseFormat.Type != "json_schema" || !request.ResponseFormat.JSONSchema.Strict {
+		t.Errorf("response format = %+v, want a strict json_schema", request.ResponseFormat)
+	}
+
+	if _, err := NewOpenAIProvider(server.URL, "", models.LLMSettings{}, false, nil).Generate(context.Background(), "analyse"); err == nil {
+		t.Error("Generate accepted a 404 response")
+	}
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+const (
+	// DefaultCandleInterval is the candle interval used when none is requested
+	DefaultCandleInterval = "5m"
+	// DefaultCandleRange is the lookback range used when none is requested
+	DefaultCandleRange = "2d"
+)
+
+// ErrInvalidCandleSettings is returned when a requested candle interval or range is not supported
+var ErrInvalidCandleSettings = errors.New("invalid candle settings")
+
+// supportedIntervals lists the candle intervals accepted by the signal pipeline
+var supportedIntervals = map[string]bool{
+	"1m":  true,
+	"5m":  true,
+	"15m": true,
+	"1h":  true,
+	"1d":  true,
+}
+
+// intervalDurations lists the time covered by one candle of each supported interval
+var intervalDurations = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// maxIntervalRanges lists the longest lookback range supported for each intraday interval.
+// The limits follow Yahoo Finance, which rejects longer ranges for these intervals.
+var maxIntervalRanges = map[string]time.Duration{
+	"1m":  7 * 24 * time.Hour,
+	"5m":  60 * 24 * time.Hour,
+	"15m": 60 * 24 * time.Hour,
+	"1h":  730 * 24 * time.Hour,
+}
+
+// MarketDataProvider fetches candlestick data from a market data source
+type MarketDataProvider interface {
+	// Name returns the name used to select the provider in configuration
+	Name() string
+	// FetchCandles fetches candles for a ticker (e.g. "BBCA.JK") at the given interval (e.g. "5m") and range (e.g. "2d")
+	FetchCandles(ctx context.Context, symbol, interval, dataRange string) ([]models.OHLCData, error)
+}
+
+// MarketDataRegistry holds the registered market data providers and selects one per symbol
+type MarketDataRegistry struct {
+	providers       map[string]MarketDataProvider
+	defaultProvider string
+	symbolProviders map[string]string
+	mutex           sync.RWMutex
+}
+
+// NewMarketDataRegistry creates a new market data registry
+func NewMarketDataRegistry(defaultProvider string, symbolProviders map[string]string) *MarketDataRegistry {
+	overrides := make(map[string]string, len(symbolProviders))
+	for symbol, provider := range symbolProviders {
+		overrides[strings.ToUpper(symbol)] = strings.ToLower(provider)
+	}
+
+	return &MarketDataRegistry{
+		providers:       make(map[string]MarketDataProvider),
+		defaultProvider: strings.ToLower(defaultProvider),
+		symbolProviders: overrides,
+	}
+}
+
+// Register adds a provider to the registry, replacing any provider with the same name
+func (r *MarketDataRegistry) Register(provider MarketDataProvider) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.providers[strings.ToLower(provider.Name())] = provider
+}
+
+// ProviderFor returns the provider configured for a symbol, falling back to the default provider
+func (r *MarketDataRegistry) ProviderFor(symbol models.Symbol) (MarketDataProvider, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	name := symbolSetting(r.symbolProviders, symbol)
+	if name == "" {
+		name = r.defaultProvider
+	}
+
+	provider, exists := r.providers[name]
+	if !exists {
+		return nil, fmt.Errorf("market data provider %q is not registered", name)
+	}
+
+	return provider, nil
+}
+
+// FetchCandles fetches candles for a symbol using the provider configured for it
+func (r *MarketDataRegistry) FetchCandles(ctx context.Context, symbol models.Symbol, interval, dataRange string) ([]models.OHLCData, error) {
+	provider, err := r.ProviderFor(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	ohlcData, err := provider.FetchCandles(ctx, symbol.Ticker, interval, dataRange)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", provider.Name(), err)
+	}
+
+	return ohlcData, nil
+}
+
+// Validate checks that the default provider and every per-symbol override refer to registered providers
+func (r *MarketDataRegistry) Validate() error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if _, exists := r.providers[r.defaultProvider]; !exists {
+		return fmt.Errorf("default market data provider %q is not registered", r.defaultProvider)
+	}
+	for symbol, name := range r.symbolProviders {
+		if _, exists := r.providers[name]; !exists {
+			return fmt.Errorf("market data provider %q configured for %s is not registered", name, symbol)
+		}
+	}
+	return nil
+}
+
+// ProviderNames returns the names of all registered providers
+func (r *MarketDataRegistry) ProviderNames() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	return names
+}
+
+// parseRangeDuration converts a Yahoo-style range (e.g. "2d", "1mo", "1y") to a duration.
+// A zero duration means the range is unbounded ("max").
+func parseRangeDuration(dataRange string) (time.Duration, error) {
+	dataRange = strings.ToLower(strings.TrimSpace(dataRange))
+	if dataRange == "" || dataRange == "max" {
+		return 0, nil
+	}
+
+	units := []struct {
+		suffix string
+		unit   time.Duration
+	}{
+		{"mo", 30 * 24 * time.Hour},
+		{"wk", 7 * 24 * time.Hour},
+		{"d", 24 * time.Hour},
+		{"y", 365 * 24 * time.Hour},
+	}
+
+	for _, u := range units {
+		if !strings.HasSuffix(dataRange, u.suffix) {
+			continue
+		}
+		count, err := strconv.Atoi(strings.TrimSuffix(dataRange, u.suffix))
+		if err != nil || count <= 0 {
+			return 0, fmt.Errorf("invalid range: %s", dataRange)
+		}
+		return time.Duration(count) * u.unit, nil
+	}
+
+	return 0, fmt.Errorf("invalid range: %s", dataRange)
+}
+
+// ValidateCandleSettings checks that an interval and range are supported
+func ValidateCandleSettings(interval, dataRange string) error {
+	if !supportedIntervals[interval] {
+		return fmt.Errorf("%w: unsupported interval %s (supported: 1m, 5m, 15m, 1h, 1d)", ErrInvalidCandleSettings, interval)
+	}
+
+	rangeDuration, err := parseRangeDuration(dataRange)
+	if err != nil || rangeDuration == 0 {
+		return fmt.Errorf("%w: unsupported range %s", ErrInvalidCandleSettings, dataRange)
+	}
+
+	if maxRange, limited := maxIntervalRanges[interval]; limited && rangeDuration > maxRange {
+		return fmt.Errorf("%w: %s candles are only available for ranges up to %d days (requested %s)",
+			ErrInvalidCandleSettings, interval, int(maxRange.Hours()/24), dataRange)
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// Notifier delivers generated signals and bulk analysis summaries to a chat, such as a Telegram
+// chat. A summary shared by several chats is delivered to each of them.
+type Notifier interface {
+	// Name returns the notifier name used in logs and dispatch reasons
+	Name() string
+
+	// NotifySignal delivers a signal that the alert policy allowed for the chat
+	NotifySignal(chatID string, signal *models.TradingSignal) error
+
+	// NotifySummary delivers the summary of a completed bulk analysis to the chat
+	NotifySummary(chatID string, summary *models.SignalSummary) error
+}
+
+// BroadcastNotifier delivers generated signals and bulk analysis summaries to a destination that
+// is not a chat, such as a webhook. Each event is delivered once, naming the chats it was made
+// for, however many chats share it.
+type BroadcastNotifier interface {
+	// Name returns the notifier name used in logs and dispatch reasons
+	Name() string
+
+	// BroadcastSignal delivers a signal that the alert policy allowed for the chats
+	BroadcastSignal(chatIDs []string, signal *models.TradingSignal) error
+
+	// BroadcastSummary delivers the summary of a completed bulk analysis made for the chats
+	BroadcastSummary(chatIDs []string, summary *models.SignalSummary) error
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// DefaultOpenAIModel is the model requested from an OpenAI-compatible endpoint when none is configured
+const DefaultOpenAIModel = "gpt-4o-mini"
+
+// OpenAIProvider completes prompts with any OpenAI-compatible chat completions endpoint,
+// including local servers such as Ollama and llama.cpp
+type OpenAIProvider struct {
+	baseURL          string
+	apiKey           string
+	settings         models.LLMSettings
+	structuredOutput bool
+	client           *http.Client
+	dependency       *Dependency
+}
+
+// openAIChatRequest is the chat completions request body
+type openAIChatRequest struct {
+	Model       string              `json:"model"`
+	Messages    []openAIChatMessage `json:"messages"`
+	Temperature float64             `json:"temperature"`
+	TopP        float64             `json:"top_p,omitempty"`
+	TopK        int                 `json:"top_k,omitempty"` // Not part of the OpenAI API; honoured by local servers
+
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+}
+
+// openAIResponseFormat constrains the completion to a JSON schema
+type openAIResponseFormat struct {
+	Type       string           `json:"type"` // "json_schema"
+	JSONSchema openAIJSONSchema `json:"json_schema"`
+}
+
+// openAIJSONSchema is a named JSON schema for structured output
+type openAIJSONSchema struct {
+	Name   string         `json:"name"`
+	Strict bool           `json:"strict"`
+	Schema map[string]any `json:"schema"`
+}
+
+// openAIChatMessage is a chat message
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAIChatResponse is the part of the chat completions response that is used
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIChatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// NewOpenAIProvider creates a provider for the chat completions endpoint under baseURL
+// (e.g. "https://api.openai.com/v1" or "http://localhost:11434/v1"). The API key may be
+// empty for local servers. With structuredOutput, JSON responses are requested with a
+// json_schema response format; disable it for servers that do not support one. Calls are
+// guarded by dependency.
+func NewOpenAIProvider(baseURL, apiKey string, settings models.LLMSettings, structuredOutput bool, dependency *Dependency) *OpenAIProvider {
+	if settings.Model == "" {
+		settings.Model = DefaultOpenAIModel
+	}
+	return &OpenAIProvider{
+		baseURL:          strings.TrimRight(baseURL, "/"),
+		apiKey:           apiKey,
+		settings:         settings,
+		structuredOutput: structuredOutput,
+		client: &http.Client{
+			Timeout: 120 * time.Second,
+		},
+		dependency: dependency,
+	}
+}
+
+// Name returns the provider name
+func (o *OpenAIProvider) Name() string {
+	return "openai"
+}
+
+// Model returns the model name
+func (o *OpenAIProvider) Model() string {
+	return o.settings.Model
+}
+
+// Generate sends the prompt as a single user message and returns the first choice
+func (o *OpenAIProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	return o.complete(ctx, prompt, nil)
+}
+
+// GenerateJSON sends the prompt with a json_schema response format when structured output is enabled
+func (o *OpenAIProvider) GenerateJSON(ctx context.Context, prompt string, schema map[string]any) (string, error) {
+	if !o.structuredOutput {
+		return o.complete(ctx, prompt, nil)
+	}
+	return o.complete(ctx, prompt, &openAIResponseFormat{
+		Type:       "json_schema",
+		JSONSchema: openAIJSONSchema{Name: "trading_signal", Strict: true, Schema: schema},
+	})
+}
+
+// complete calls the chat completions endpoint
+func (o *OpenAIProvider) complete(ctx context.Context, prompt string, format *openAIResponseFormat) (string, error) {
+	body, err := json.Marshal(openAIChatRequest{
+		Model:          o.settings.Model,
+		Messages:       []openAIChatMessage{{Role: "user", Content: prompt}},
+		Temperature:    o.settings.Temperature,
+		TopP:           o.settings.TopP,
+		TopK:           o.settings.TopK,
+		ResponseFormat: format,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	var completion openAIChatResponse
+	err = o.dependency.Call(ctx, func(ctx context.Context) error {
+		return o.post(ctx, body, &completion)
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(completion.Choices) == 0 {
+		return "", fmt.Errorf("no choices in chat completion")
+	}
+
+	return completion.Choices[0].Message.Content, nil
+}
+
+// post sends a chat completions request and decodes the response
+func (o *OpenAIProvider) post(ctx context.Context, body []byte, completion *openAIChatResponse) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", o.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return transportError(ctx, fmt.Errorf("failed to call %s: %w", o.baseURL, err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return statusError(resp, fmt.Errorf("chat completions endpoint returned status: %d, body: %s", resp.StatusCode, string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(completion); err != nil {
+		return fmt.Errorf("failed to decode chat completion: %w", err)
+	}
+	return nil
+}
+
+// Close does nothing; the HTTP client holds no resources that need releasing
+func (o *OpenAIProvider) Close() error {
+	return nil
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// Outcome statuses recorded for tracked signals
+const (
+	OutcomePending   = "pending"
+	OutcomeOpen      = "open"
+	OutcomeTargetHit = "target_hit"
+	OutcomeStopHit   = "stop_hit"
+	OutcomeNotFilled = "not_filled"
+	OutcomeExpired   = "expired"
+)
+
+// IsFinalOutcome reports whether an outcome status will not change anymore
+func IsFinalOutcome(status string) bool {
+	switch status {
+	case OutcomeTargetHit, OutcomeStopHit, OutcomeNotFilled, OutcomeExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// EvaluateOutcome replays the candles that started at or after a BUY/SELL signal was generated
+// and up to the deadline. The entry fills at the buy price when a candle trades through it, then
+// the first candle touching the target or the stop closes the trade. When one candle touches both,
+// the stop is assumed to have been hit first. Once now is past the deadline an unfilled signal is
+// "not_filled" and an open trade is "expired" at the last close.
+func EvaluateOutcome(signal *models.TradingSignal, candles []models.OHLCData, deadline, now time.Time) *models.SignalOutcome {
+	direction := strings.ToUpper(signal.Signal)
+	isBuy := direction == "BUY"
+	entry := signal.BuyPrice
+
+	outcome := &models.SignalOutcome{
+		SignalID:      signal.ID,
+		Symbol:        signal.StockSymbol,
+		Exchange:      signal.Exchange,
+		SignalType:    direction,
+		GeneratedAt:   signal.GeneratedAt,
+		EntryPrice:    entry,
+		TargetPrice:   signal.TargetPrice,
+		StopLoss:      signal.StopLoss,
+		Status:        OutcomePending,
+		LastCheckedAt: now,
+	}
+
+	var best, worst, lastClose float64
+	for _, candle := range candles {
+		if candle.Timestamp.Before(signal.GeneratedAt) {
+			continue
+		}
+		if candle.Timestamp.After(deadline) {
+			break
+		}
+		timestamp := candle.Timestamp
+		outcome.LastCandleAt = &timestamp
+		lastClose = candle.Close
+
+		if !outcome.EntryFilled {
+			if (isBuy && candle.Low > entry) || (!isBuy && candle.High < entry) {
+				continue
+			}
+			outcome.EntryFilled = true
+			outcome.EntryFilledAt = &timestamp
+			outcome.Status = OutcomeOpen
+			best, worst = entry, entry
+		}
+
+		if isBuy {
+			best, worst = math.Max(best, candle.High), math.Min(worst, candle.Low)
+		} else {
+			best, worst = math.Min(best, candle.Low), math.Max(worst, candle.High)
+		}
+
+		stopHit := (isBuy && candle.Low <= signal.StopLoss) || (!isBuy && candle.High >= signal.StopLoss)
+		targetHit := (isBuy && candle.High >= signal.TargetPrice) || (!isBuy && candle.Low <= signal.TargetPrice)
+		switch {
+		case stopHit:
+			closeOutcome(outcome, OutcomeStopHit, signal.StopLoss, timestamp)
+		case targetHit:
+			closeOutcome(outcome, OutcomeTargetHit, signal.TargetPrice, timestamp)
+		default:
+			continue
+		}
+		break
+	}
+
+	if outcome.EntryFilled && entry > 0 {
+		if isBuy {
+			outcome.MaxFavorable, outcome.MaxAdverse = best-entry, entry-worst
+		} else {
+			outcome.MaxFavorable, outcome.MaxAdverse = entry-best, worst-entry
+		}
+		// An excursion can only be favourable or adverse, never negative
+		outcome.MaxFavorable = math.Max(outcome.MaxFavorable, 0)
+		outcome.MaxAdverse = math.Max(outcome.MaxAdverse, 0)
+		outcome.MaxFavorablePct = outcome.MaxFavorable / entry * 100
+		outcome.MaxAdversePct = outcome.MaxAdverse / entry * 100
+	}
+
+	if !IsFinalOutcome(outcome.Status) && now.After(deadline) {
+		if outcome.EntryFilled {
+			closeOutcome(outcome, OutcomeExpired, lastClose, deadline)
+		} else {
+			outcome.Status = OutcomeNotFilled
+			outcome.OutcomeAt = &deadline
+			outcome.TimeToOutcomeMinutes = deadline.Sub(signal.GeneratedAt).Minutes()
+		}
+	}
+
+	return outcome
+}
+
+// closeOutcome records the exit of a filled trade
+func closeOutcome(outcome *models.SignalOutcome, status string, exitPrice float64, at time.Time) {
+	outcome.Status = status
+	outcome.ExitPrice = exitPrice
+	outcome.OutcomeAt = &at
+	outcome.TimeToOutcomeMinutes = at.Sub(outcome.GeneratedAt).Minutes()
+	if outcome.EntryPrice > 0 {
+		outcome.ReturnPct = (exitPrice - outcome.EntryPrice) / outcome.EntryPrice * 100
+		if outcome.SignalType == "SELL" {
+			outcome.ReturnPct = -outcome.ReturnPct
+		}
+	}
+}
+
+// SummarizeOutcomes aggregates tracked outcomes into hit rates and averages
+func SummarizeOutcomes(outcomes []*models.SignalOutcome) *models.OutcomeStats {
+	stats := &models.OutcomeStats{Tracked: len(outcomes)}
+
+	var filled, closed, excursions, timed int
+	var returns, favorable, adverse, minutes float64
+	for _, outcome := range outcomes {
+		switch outcome.Status {
+		case OutcomePending:
+			stats.Pending++
+		case OutcomeOpen:
+			stats.Open++
+		case OutcomeNotFilled:
+			stats.NotFilled++
+		case OutcomeTargetHit:
+			stats.TargetHits++
+		case OutcomeStopHit:
+			stats.StopHits++
+		case OutcomeExpired:
+			stats.Expired++
+		}
+
+		if outcome.EntryFilled {
+			filled++
+			excursions++
+			favorable += outcome.MaxFavorablePct
+			adverse += outcome.MaxAdversePct
+		}
+		if outcome.Status == OutcomeTargetHit || outcome.Status == OutcomeStopHit || outcome.Status == OutcomeExpired {
+			closed++
+			returns += outcome.ReturnPct
+		}
+		if outcome.Status == OutcomeTargetHit || outcome.Status == OutcomeStopHit {
+			timed++
+			minutes += outcome.TimeToOutcomeMinutes
+		}
+	}
+
+	// Pending signals may still fill, so they are left out of the fill rate
+	if decided := filled + stats.NotFilled; decided > 0 {
+		stats.FillRate = float64(filled) / float64(decided) * 100
+	}
+	if hits := stats.TargetHits + stats.StopHits; hits > 0 {
+		stats.HitRate = float64(stats.TargetHits) / float64(hits) * 100
+	}
+	if closed > 0 {
+		stats.AvgReturnPct = returns / float64(closed)
+	}
+	if excursions > 0 {
+		stats.AvgMaxFavorablePct = favorable / float64(excursions)
+		stats.AvgMaxAdversePct = adverse / float64(excursions)
+	}
+	if timed > 0 {
+		stats.AvgTimeToOutcomeMins = minutes / float64(timed)
+	}
+
+	return stats
+}
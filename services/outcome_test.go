@@ -0,0 +1,75 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+func TestEvaluateOutcome(t *testing.T) {
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	deadline := start.Add(time.Hour)
+	candle := func(minute int, high, low, close float64) models.OHLCData {
+		return models.OHLCData{Timestamp: start.Add(time.Duration(minute) * time.Minute), High: high, Low: low, Close: close}
+	}
+	buy := &models.TradingSignal{ID: "sig_1", Signal: "BUY", GeneratedAt: start, BuyPrice: 1000, TargetPrice: 1050, StopLoss: 980}
+	sell := &models.TradingSignal{ID: "sig_2", Signal: "SELL", GeneratedAt: start, BuyPrice: 1000, TargetPrice: 950, StopLoss: 1020}
+
+	tests := []struct {
+		name       string
+		signal     *models.TradingSignal
+		candles    []models.OHLCData
+		now        time.Time
+		wantStatus string
+		wantExit   float64
+		wantReturn float64
+	}{
+		{"pending before fill", buy, []models.OHLCData{candle(5, 1020, 1005, 1010)}, start.Add(10 * time.Minute), OutcomePending, 0, 0},
+		{"open after fill", buy, []models.OHLCData{candle(5, 1010, 995, 1005)}, start.Add(10 * time.Minute), OutcomeOpen, 0, 0},
+		{"buy target hit", buy, []models.OHLCData{candle(5, 1010, 995, 1005), candle(10, 1060, 1000, 1055)}, start.Add(15 * time.Minute), OutcomeTargetHit, 1050, 5},
+		{"buy stop wins when both touched", buy, []models.OHLCData{candle(5, 1060, 970, 1000)}, start.Add(10 * time.Minute), OutcomeStopHit, 980, -2},
+		{"sell target hit", sell, []models.OHLCData{candle(5, 1005, 990, 995), candle(10, 990, 940, 945)}, start.Add(15 * time.Minute), OutcomeTargetHit, 950, 5},
+		{"not filled after deadline", buy, []models.OHLCData{candle(5, 1020, 1005, 1010)}, deadline.Add(time.Minute), OutcomeNotFilled, 0, 0},
+		{"expired at last close", buy, []models.OHLCData{candle(5, 1010, 995, 1010), candle(90, 1100, 900, 1100)}, deadline.Add(time.Hour), OutcomeExpired, 1010, 1},
+		{"ignores candles before the signal", buy, []models.OHLCData{candle(-5, 1060, 970, 1000)}, start.Add(time.Minute), OutcomePending, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome := EvaluateOutcome(tt.signal, tt.candles, deadline, tt.now)
+			if outcome.Status != tt.wantStatus {
+				t.Fatalf("status = %s, want %s", outcome.Status, tt.wantStatus)
+			}
+			if outcome.ExitPrice != tt.wantExit {
+				t.Errorf("exit price = %v, want %v", outcome.ExitPrice, tt.wantExit)
+			}
+			if outcome.ReturnPct != tt.wantReturn {
+				t.Errorf("return = %v%%, want %v%%", outcome.ReturnPct, tt.wantReturn)
+			}
+		})
+	}
+}
+
+func TestSummarizeOutcomes(t *testing.T) {
+	stats := SummarizeOutcomes([]*models.SignalOutcome{
+		{Status: OutcomeTargetHit, EntryFilled: true, ReturnPct: 5},
+		{Status: OutcomeTargetHit, EntryFilled: true, ReturnPct: 3},
+		{Status: OutcomeStopHit, EntryFilled: true, ReturnPct: -2},
+		{Status: OutcomeNotFilled},
+		{Status: OutcomePending},
+	})
+
+	if stats.Tracked != 5 || stats.TargetHits != 2 || stats.StopHits != 1 || stats.NotFilled != 1 || stats.Pending != 1 {
+		t.Fatalf("unexpected counts: %+v", stats)
+	}
+	if stats.FillRate != 75 {
+		t.Errorf("fill rate = %v, want 75", stats.FillRate)
+	}
+	if want := float64(2) / float64(3) * 100; stats.HitRate != want {
+		t.Errorf("hit rate = %v, want %v", stats.HitRate, want)
+	}
+	if stats.AvgReturnPct != 2 {
+		t.Errorf("avg return = %v, want 2", stats.AvgReturnPct)
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// trackingRanges lists the candle ranges tried for outcome tracking, shortest first
+var trackingRanges = []string{"5d", "1mo", "3mo", "6mo", "1y", "2y"}
+
+// OutcomeTracker periodically polls candles after stored BUY/SELL signals and records whether
+// the entry filled and whether the target or the stop was reached first
+type OutcomeTracker struct {
+	tradingService *TradingSignalService
+	pollInterval   time.Duration
+	window         time.Duration
+	ctx            context.Context // Cancelled by Stop, abandoning a running pass
+	cancel         context.CancelFunc
+	done           chan struct{}
+}
+
+// NewOutcomeTracker creates a new outcome tracker that polls every pollInterval and tracks
+// each signal for window after it was generated
+func NewOutcomeTracker(tradingService *TradingSignalService, pollInterval, window time.Duration) (*OutcomeTracker, error) {
+	if tradingService.store == nil {
+		return nil, fmt.Errorf("outcome tracking requires the signal history: %w", ErrSignalHistoryDisabled)
+	}
+	if pollInterval <= 0 || window <= 0 {
+		return nil, errors.New("outcome tracking poll interval and window must be positive")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &OutcomeTracker{
+		tradingService: tradingService,
+		pollInterval:   pollInterval,
+		window:         window,
+		ctx:            ctx,
+		cancel:         cancel,
+		done:           make(chan struct{}),
+	}, nil
+}
+
+// Start runs the tracker in the background until Stop is called
+func (o *OutcomeTracker) Start() {
+	log.Printf("Starting outcome tracker (every %s, tracking window %s)", o.pollInterval, o.window)
+
+	go func() {
+		defer close(o.done)
+
+		ticker := time.NewTicker(o.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			o.TrackOnce(o.ctx)
+			select {
+			case <-ticker.C:
+			case <-o.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the tracker, cancelling a running pass, and waits for it to return
+func (o *OutcomeTracker) Stop() {
+	o.cancel()
+	<-o.done
+}
+
+// TrackOnce evaluates every stored BUY/SELL signal whose outcome is not final yet and
+// returns the number of outcomes saved. The pass stops early when ctx is cancelled.
+func (o *OutcomeTracker) TrackOnce(ctx context.Context) int {
+	now := time.Now()
+	store := o.tradingService.store
+
+	// Look back a day beyond the window so expiring signals get their final check
+	records, err := QueryAllSignals(store, models.SignalQuery{
+		From:        now.Add(-o.window - 24*time.Hour),
+		SignalTypes: []string{"BUY", "SELL"},
+	})
+	if err != nil {
+		log.Printf("Outcome tracker failed to query signals: %v", err)
+		return 0
+	}
+
+	candleCache := make(map[string][]models.OHLCData)
+	saved := 0
+	for _, record := range records {
+		if ctx.Err() != nil {
+			break
+		}
+		if (record.SignalType != "BUY" && record.SignalType != "SELL") || record.Signal == nil {
+			continue
+		}
+		if existing, err := store.GetOutcome(record.ID); err == nil && IsFinalOutcome(existing.Status) {
+			continue
+		}
+
+		candles, err := o.fetchCandlesSince(ctx, record, now, candleCache)
+		if err != nil {
+			log.Printf("Outcome tracker failed to fetch candles for %s: %v", record.Ticker, err)
+			continue
+		}
+
+		outcome := EvaluateOutcome(record.Signal, candles, record.Signal.GeneratedAt.Add(o.window), now)
+		if err := store.SaveOutcome(outcome); err != nil {
+			log.Printf("Outcome tracker failed to save outcome for %s: %v", record.ID, err)
+			continue
+		}
+		saved++
+
+		if IsFinalOutcome(outcome.Status) {
+			log.Printf("Signal %s (%s %s) outcome: %s", record.ID, outcome.SignalType, record.Ticker, outcome.Status)
+		}
+	}
+
+	return saved
+}
+
+// fetchCandlesSince fetches candles at the signal's interval covering the time since it was
+// generated, sharing fetches between signals for the same ticker and interval
+func (o *OutcomeTracker) fetchCandlesSince(ctx context.Context, record *models.SignalRecord, now time.Time, cache map[string][]models.OHLCData) ([]models.OHLCData, error) {
+	interval := record.Signal.Interval
+	if interval == "" {
+		interval = DefaultCandleInterval
+	}
+	dataRange := rangeCovering(interval, now.Sub(record.Signal.GeneratedAt))
+	if dataRange == "" {
+		return nil, fmt.Errorf("%w: no range available for %s candles", ErrInvalidCandleSettings, interval)
+	}
+
+	key := strings.Join([]string{record.Ticker, interval, dataRange}, "|")
+	if candles, exists := cache[key]; exists {
+		return candles, nil
+	}
+
+	symbol := models.Symbol{
+		Code:     record.Symbol,
+		Exchange: record.Signal.Exchange,
+		Ticker:   record.Ticker,
+	}
+	var candles []models.OHLCData
+	err := runStage(ctx, StageFetchCandles, o.tradingService.marketDataTimeout(), func(ctx context.Context) error {
+		var err error
+		candles, err = o.tradingService.marketData.FetchCandles(ctx, symbol, interval, dataRange)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	cache[key] = candles
+	return candles, nil
+}
+
+// rangeCovering returns the shortest supported range for an interval that covers the elapsed
+// time plus a day, or the longest supported range when none does
+func rangeCovering(interval string, elapsed time.Duration) string {
+	chosen := ""
+	for _, dataRange := range trackingRanges {
+		if ValidateCandleSettings(interval, dataRange) != nil {
+			break
+		}
+		chosen = dataRange
+		if duration, _ := parseRangeDuration(dataRange); duration >= elapsed+24*time.Hour {
+			break
+		}
+	}
+	return chosen
+}
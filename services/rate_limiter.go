@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBucket is a token-bucket rate limiter refilled at a steady rate per minute. The bucket
+// holds at most one minute's worth of tokens, so a quiet period allows a burst of that size.
+type TokenBucket struct {
+	capacity float64
+	perSec   float64
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+	mutex    sync.Mutex
+}
+
+// NewTokenBucket creates a full bucket refilled with perMinute tokens a minute, or returns nil,
+// which does not limit, when perMinute is not positive
+func NewTokenBucket(perMinute int) *TokenBucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &TokenBucket{
+		capacity: float64(perMinute),
+		perSec:   float64(perMinute) / 60,
+		tokens:   float64(perMinute),
+		last:     time.Now(),
+		now:      time.Now,
+	}
+}
+
+// Wait blocks until n tokens are available and takes them, or returns ctx's error. Requests
+// for more than the bucket holds wait for a full bucket.
+func (b *TokenBucket) Wait(ctx context.Context, n int) error {
+	if b == nil || n <= 0 {
+		return nil
+	}
+
+	for {
+		delay := b.reserve(float64(n))
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// reserve takes n tokens and returns zero, or returns how long until they are available
+func (b *TokenBucket) reserve(n float64) time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := b.now()
+	b.tokens = min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.perSec)
+	b.last = now
+
+	n = min(n, b.capacity)
+	if b.tokens >= n {
+		b.tokens -= n
+		return 0
+	}
+	return time.Duration((n - b.tokens) / b.perSec * float64(time.Second))
+}
+
+// estimateTokens approximates the number of model tokens in a text at four characters per token
+func estimateTokens(text string) int {
+	return len(text)/4 + 1
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2025, 6, 12, 9, 0, 0, 0, time.UTC)
+	bucket := NewTokenBucket(60)
+	bucket.now = func() time.Time { return now }
+	bucket.last = now
+
+	if delay := bucket.reserve(60); delay != 0 {
+		t.Fatalf("full bucket delayed %s", delay)
+	}
+	if delay := bucket.reserve(2); delay != 2*time.Second {
+		t.Errorf("empty bucket delay = %s, want 2s at one token a second", delay)
+	}
+
+	now = now.Add(5 * time.Second)
+	if delay := bucket.reserve(5); delay != 0 {
+		t.Errorf("delay after refilling 5 tokens = %s, want none", delay)
+	}
+	if delay := bucket.reserve(600); delay != 60*time.Second {
+		t.Errorf("oversized request delay = %s, want a full bucket", delay)
+	}
+
+	if NewTokenBucket(0) != nil || NewTokenBucket(0).Wait(context.Background(), 10) != nil {
+		t.Error("a zero rate should not limit")
+	}
+
+	drained := NewTokenBucket(1)
+	if err := drained.Wait(context.Background(), 1); err != nil {
+		t.Fatalf("Wait on a full bucket returned %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := drained.Wait(ctx, 1); err != context.Canceled {
+		t.Errorf("Wait on a cancelled context returned %v", err)
+	}
+}
+
+// delayedStrategy waits longer for earlier stocks so that they finish last
+type delayedStrategy struct {
+	delays map[string]time.Duration
+}
+
+func (d *delayedStrategy) Name() string { return "delayed" }
+
+func (d *delayedStrategy) GenerateTradingSignal(ctx context.Context, input *models.SignalInput) (*models.TradingSignal, error) {
+	time.Sleep(d.delays[input.Symbol.Code])
+	if input.Symbol.Code == "FAIL" {
+		return nil, fmt.Errorf("no signal for %s", input.Symbol.Code)
+	}
+	signal := &models.TradingSignal{Signal: "WAIT", Confidence: 50}
+	annotateSignal(signal, input)
+	return signal, nil
+}
+
+func TestAnalyzeAllKeepsConfiguredOrder(t *testing.T) {
+	marketData := NewMarketDataRegistry("static", nil)
+	marketData.Register(&staticMarketData{candles: llmInput().OHLCData})
+	strategies := NewStrategyRegistry("delayed", nil)
+	strategies.Register(&delayedStrategy{delays: map[string]time.Duration{"AAA": 30 * time.Millisecond, "FAIL": 20 * time.Millisecond, "BBB": 10 * time.Millisecond}})
+
+	service := &TradingSignalService{
+		marketData: marketData,
+		symbols:    NewSymbolResolver(nil, true),
+		validator:  NewSignalValidator(2),
+		strategies: strategies,
+		config: &models.Config{
+			StockSymbols:    []string{"AAA.US", "FAIL.US", "BBB.US", "CCC.US"},
+			CandleInterval:  "5m",
+			CandleRange:     "1d",
+			BulkConcurrency: 4,
+		},
+	}
+
+	result := service.analyzeAll(context.Background(), nil, "")
+	if result.err != nil {
+		t.Fatalf("analyzeAll returned error: %v", result.err)
+	}
+
+	summary := result.summary
+	var order []string
+	for _, signal := range summary.HoldSignals {
+		order = append(order, signal.StockSymbol)
+	}
+	if fmt.Sprint(order) != "[AAA BBB CCC]" || fmt.Sprint(summary.FailedSignals) != "[FAIL.US]" || summary.TotalAnalyzed != 4 {
+		t.Errorf("summary lists %v, failed %v of %d, want [AAA BBB CCC] and [FAIL.US] of 4", order, summary.FailedSignals, summary.TotalAnalyzed)
+	}
+}
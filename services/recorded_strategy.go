@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// ErrNoRecordedSignal is returned by the recorded strategy when no stored signal matches a run
+var ErrNoRecordedSignal = errors.New("no recorded signal")
+
+// recordedSignalTolerance is how far a stored signal may have been generated from a replayed run
+const recordedSignalTolerance = 30 * time.Minute
+
+// RecordedStrategy replays signals from the signal history instead of calling a model,
+// so past AI signals can be backtested without new API calls
+type RecordedStrategy struct {
+	store SignalStore
+}
+
+// NewRecordedStrategy creates a strategy replaying the signals in the store
+func NewRecordedStrategy(store SignalStore) *RecordedStrategy {
+	return &RecordedStrategy{
+		store: store,
+	}
+}
+
+// Name returns the strategy name
+func (r *RecordedStrategy) Name() string {
+	return "recorded"
+}
+
+// GenerateTradingSignal returns the stored signal for the symbol and interval generated closest
+// to the input's time, within recordedSignalTolerance
+func (r *RecordedStrategy) GenerateTradingSignal(ctx context.Context, input *models.SignalInput) (*models.TradingSignal, error) {
+	asOf := input.AsOf
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+
+	records, err := r.store.QuerySignals(models.SignalQuery{
+		Symbol: input.Symbol.Ticker,
+		From:   asOf.Add(-recordedSignalTolerance),
+		To:     asOf.Add(recordedSignalTolerance),
+		Limit:  MaxQueryLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var closest *models.TradingSignal
+	var closestGap time.Duration
+	for _, record := range records {
+		if record.Signal == nil || (record.Inputs != nil && record.Inputs.Interval != input.Interval) {
+			continue
+		}
+		gap := record.Signal.GeneratedAt.Sub(asOf)
+		if gap < 0 {
+			gap = -gap
+		}
+		if gap <= recordedSignalTolerance && (closest == nil || gap < closestGap) {
+			closest, closestGap = record.Signal, gap
+		}
+	}
+	if closest == nil {
+		return nil, fmt.Errorf("%w for %s %s at %s", ErrNoRecordedSignal, input.Symbol.Ticker, input.Interval, asOf.Format(time.RFC3339))
+	}
+
+	// Replay the stored decision on the recomputed analysis; rules and validation run again
+	signal := *closest
+	signal.Validation = nil
+	signal.Dispatch = nil
+	signal.LevelWarnings = nil
+	annotateSignal(&signal, input)
+	return &signal, nil
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// ErrCircuitOpen is returned without calling a dependency while its circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+// RetryPolicy configures how failed calls to a dependency are retried
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts per call including the first; values below 1 mean one
+	BaseDelay   time.Duration // Backoff before the first retry, doubled for each further retry
+	MaxDelay    time.Duration // Upper bound of the backoff and of a server-requested delay
+}
+
+// RetryableError marks a failure as transient, e.g. a 429 or 5xx response or a network error
+type RetryableError struct {
+	Err        error
+	RetryAfter time.Duration // Delay requested by the server, zero when none
+}
+
+// Error returns the underlying error
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// retryableStatus reports whether a response status is worth retrying
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// statusError marks err as retryable when the response status is, honouring its Retry-After header
+func statusError(resp *http.Response, err error) error {
+	if !retryableStatus(resp.StatusCode) {
+		return err
+	}
+	return &RetryableError{Err: err, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())}
+}
+
+// transportError marks a failed request as retryable unless the caller gave up on it
+func transportError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+	return &RetryableError{Err: err}
+}
+
+// publicError returns an error's text for API clients, dropping the request URL of a failed
+// HTTP request, which may carry credentials such as the Telegram bot token
+func publicError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Sprintf("%s request failed: %v", urlErr.Op, urlErr.Err)
+	}
+	return err.Error()
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// CircuitBreaker stops calls to a failing dependency. After threshold consecutive failed calls
+// it opens and rejects calls for the cooldown, then lets a single trial call through: success
+// closes it again and failure reopens it.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	state     string
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	now       func() time.Time
+	mutex     sync.Mutex
+}
+
+// NewCircuitBreaker creates a closed circuit breaker; a non-positive threshold disables it
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     CircuitClosed,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a call may proceed
+func (b *CircuitBreaker) Allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = CircuitHalfOpen
+		b.probing = true
+		return true
+	case CircuitHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Success records a call that reached the dependency and closes the breaker
+func (b *CircuitBreaker) Success() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.state = CircuitClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure records a failed call and opens the breaker at the threshold or after a failed trial
+func (b *CircuitBreaker) Failure() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.threshold > 0 && (b.state == CircuitHalfOpen || b.failures >= b.threshold) {
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Abandon records a call given up by its caller, which says nothing about the dependency
+func (b *CircuitBreaker) Abandon() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.probing = false
+}
+
+// Dependency guards calls to an external service with retries, a circuit breaker and optional
+// rate limits. A nil dependency calls straight through.
+type Dependency struct {
+	name     string
+	retry    RetryPolicy
+	breaker  *CircuitBreaker
+	requests *TokenBucket // Taken once per attempt, nil for no limit
+	tokens   *TokenBucket // Taken per attempt by the call's token estimate, nil for no limit
+}
+
+// NewDependency creates a guard for the named external service
+func NewDependency(name string, retry RetryPolicy, breaker *CircuitBreaker) *Dependency {
+	return &Dependency{
+		name:    name,
+		retry:   retry,
+		breaker: breaker,
+	}
+}
+
+// Limit rate-limits the dependency's attempts by request and by model token; either bucket may
+// be nil. It returns the dependency for chaining.
+func (d *Dependency) Limit(requests, tokens *TokenBucket) *Dependency {
+	d.requests = requests
+	d.tokens = tokens
+	return d
+}
+
+// Name returns the dependency name
+func (d *Dependency) Name() string {
+	return d.name
+}
+
+// Call runs fn, retrying *RetryableError failures with exponential backoff and jitter or after
+// the delay the server asked for. Only calls that still fail with a retryable error count
+// against the circuit breaker; while it is open, Call fails fast with ErrCircuitOpen.
+func (d *Dependency) Call(ctx context.Context, fn func(ctx context.Context) error) error {
+	return d.CallWithTokens(ctx, 0, fn)
+}
+
+// CallWithTokens is Call for a request estimated to use the given number of model tokens,
+// which every attempt takes from the token rate limit
+func (d *Dependency) CallWithTokens(ctx context.Context, tokens int, fn func(ctx context.Context) error) error {
+	if d == nil {
+		return fn(ctx)
+	}
+	if !d.breaker.Allow() {
+		return fmt.Errorf("%s: %w", d.name, ErrCircuitOpen)
+	}
+
+	err := d.attempt(ctx, tokens, fn)
+	var retryable *RetryableError
+	switch {
+	case err == nil:
+		d.breaker.Success()
+	case ctx.Err() != nil:
+		d.breaker.Abandon()
+	case errors.As(err, &retryable):
+		d.breaker.Failure()
+	default:
+		// The dependency answered; the request itself was wrong
+		d.breaker.Success()
+	}
+	return err
+}
+
+// attempt runs fn until it succeeds, fails permanently or runs out of attempts
+func (d *Dependency) attempt(ctx context.Context, tokens int, fn func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		if err := d.requests.Wait(ctx, 1); err != nil {
+			return err
+		}
+		if err := d.tokens.Wait(ctx, tokens); err != nil {
+			return err
+		}
+
+		err := fn(ctx)
+		var retryable *RetryableError
+		if err == nil || !errors.As(err, &retryable) || attempt >= d.retry.MaxAttempts {
+			return err
+		}
+
+		delay := d.backoff(attempt)
+		if retryable.RetryAfter > 0 {
+			if d.retry.MaxDelay > 0 && retryable.RetryAfter > d.retry.MaxDelay {
+				// Retrying sooner than the server asked would only be rejected again
+				return err
+			}
+			delay = retryable.RetryAfter
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+// backoff returns the delay before the retry following the given attempt: the base delay
+// doubled per attempt, capped at the maximum, with the upper half randomised
+func (d *Dependency) backoff(attempt int) time.Duration {
+	delay := d.retry.BaseDelay
+	for i := 1; i < attempt && (d.retry.MaxDelay <= 0 || delay < d.retry.MaxDelay); i++ {
+		delay *= 2
+	}
+	if d.retry.MaxDelay > 0 && delay > d.retry.MaxDelay {
+		delay = d.retry.MaxDelay
+	}
+	if delay <= 1 {
+		return delay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// Status returns the state of the dependency's circuit breaker
+func (d *Dependency) Status() models.DependencyStatus {
+	d.breaker.mutex.Lock()
+	defer d.breaker.mutex.Unlock()
+
+	status := models.DependencyStatus{
+		Name:                d.name,
+		State:               d.breaker.state,
+		ConsecutiveFailures: d.breaker.failures,
+	}
+	if d.breaker.state != CircuitClosed {
+		openedAt := d.breaker.openedAt
+		retryAt := openedAt.Add(d.breaker.cooldown)
+		status.OpenedAt = &openedAt
+		status.RetryAt = &retryAt
+	}
+	return status
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// testRetryPolicy retries quickly so that tests do not sleep
+var testRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+func TestDependencyCall(t *testing.T) {
+	transient := &RetryableError{Err: errors.New("503 service unavailable")}
+	permanent := errors.New("400 bad request")
+
+	tests := []struct {
+		name      string
+		errors    []error // Returned by successive attempts, nil once exhausted
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "succeeds first time", wantCalls: 1},
+		{name: "retries transient errors", errors: []error{transient, transient}, wantCalls: 3},
+		{name: "gives up after the last attempt", errors: []error{transient, transient, transient}, wantCalls: 3, wantErr: transient},
+		{name: "does not retry permanent errors", errors: []error{permanent}, wantCalls: 1, wantErr: permanent},
+		{
+			name:      "gives up when the server asks for too long a wait",
+			errors:    []error{&RetryableError{Err: transient.Err, RetryAfter: time.Minute}},
+			wantCalls: 1,
+			wantErr:   transient.Err,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dependency := NewDependency("test", testRetryPolicy, NewCircuitBreaker(0, 0))
+			calls := 0
+			err := dependency.Call(context.Background(), func(ctx context.Context) error {
+				calls++
+				if calls <= len(tt.errors) {
+					return tt.errors[calls-1]
+				}
+				return nil
+			})
+
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2025, 6, 12, 9, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+	dependency := NewDependency("yahoo", RetryPolicy{MaxAttempts: 1}, breaker)
+
+	calls := 0
+	failing := func(ctx context.Context) error {
+		calls++
+		return &RetryableError{Err: errors.New("502 bad gateway")}
+	}
+
+	dependency.Call(context.Background(), failing)
+	dependency.Call(context.Background(), failing)
+	if status := dependency.Status(); status.State != CircuitOpen || status.ConsecutiveFailures != 2 || status.RetryAt == nil {
+		t.Fatalf("status after two failures = %+v, want open", status)
+	}
+
+	if err := dependency.Call(context.Background(), failing); !errors.Is(err, ErrCircuitOpen) || calls != 2 {
+		t.Fatalf("open breaker returned %v after %d calls, want ErrCircuitOpen without a call", err, calls)
+	}
+
+	// After the cooldown a single trial call goes through; its failure reopens the breaker
+	now = now.Add(time.Minute)
+	dependency.Call(context.Background(), failing)
+	if status := dependency.Status(); status.State != CircuitOpen || calls != 3 {
+		t.Fatalf("status after a failed trial = %+v with %d calls, want open after 3", status, calls)
+	}
+
+	now = now.Add(time.Minute)
+	if err := dependency.Call(context.Background(), func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("trial call returned error: %v", err)
+	}
+	if status := dependency.Status(); status.State != CircuitClosed || status.ConsecutiveFailures != 0 || status.OpenedAt != nil {
+		t.Errorf("status after a successful trial = %+v, want closed", status)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 6, 12, 9, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"7":                             7 * time.Second,
+		"soon":                          0,
+		"Thu, 12 Jun 2025 09:00:30 GMT": 30 * time.Second,
+		"Thu, 12 Jun 2025 08:59:00 GMT": 0,
+	}
+	for value, want := range tests {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func TestOpenAIProviderRetriesRateLimits(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "ok"}}]}`))
+	}))
+	defer server.Close()
+
+	dependency := NewDependency("openai", testRetryPolicy, NewCircuitBreaker(5, time.Minute))
+	text, err := NewOpenAIProvider(server.URL, "", models.LLMSettings{}, false, dependency).Generate(context.Background(), "analyse")
+	if err != nil || text != "ok" {
+		t.Fatalf("Generate = %q, %v, want ok after a retry", text, err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("requests = %d, want 2", requests.Load())
+	}
+}
+
+func TestPublicError(t *testing.T) {
+	err := &RetryableError{Err: fmt.Errorf("failed to send Telegram message: %w", &url.Error{
+		Op:  "Post",
+		URL: "https://api.telegram.org/bot123:SECRET/sendMessage",
+		Err: errors.New("connection reset by peer"),
+	})}
+	if got := publicError(err); strings.Contains(got, "SECRET") || !strings.Contains(got, "connection reset by peer") {
+		t.Errorf("publicError = %q, want the cause without the URL", got)
+	}
+	if got := publicError(errors.New("Telegram API returned status: 400")); got != "Telegram API returned status: 400" {
+		t.Errorf("publicError changed a plain error: %q", got)
+	}
+}
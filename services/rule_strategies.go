@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/indicators"
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// Parameters shared by the rule-based strategies
+const (
+	// ruleStopATRMultiple is the stop distance from the entry in ATRs
+	ruleStopATRMultiple = 1.5
+	// ruleBaseConfidence is the confidence of a rule signal before confirmations are added
+	ruleBaseConfidence = 55
+	// ruleMaxConfidence caps the confidence of rule signals, which never see the whole picture
+	ruleMaxConfidence = 90
+
+	rsiOversold   = 30.0
+	rsiOverbought = 70.0
+
+	// vwapVolumeMultiple is the volume, relative to the recent average, that confirms a VWAP breakout
+	vwapVolumeMultiple = 1.5
+	vwapVolumeLookback = 20
+
+	// openingRangeMinutes is the length of the opening range from the first candle of the session
+	openingRangeMinutes = 30
+)
+
+// errNotEnoughCandles explains WAIT signals of rule strategies without enough history
+var errNotEnoughCandles = errors.New("not enough candles for the strategy's indicators")
+
+// RuleStrategies returns the native rule-based strategies, which run without an AI model
+func RuleStrategies() []Strategy {
+	return []Strategy{
+		&EMACrossoverStrategy{},
+		&RSIMeanReversionStrategy{},
+		&VWAPBreakoutStrategy{},
+		&OpeningRangeBreakoutStrategy{},
+	}
+}
+
+// EMACrossoverStrategy buys when the fast EMA crosses above the slow EMA and sells on the
+// opposite cross, with RSI and MACD momentum raising the confidence
+type EMACrossoverStrategy struct{}
+
+// Name returns the strategy name
+func (s *EMACrossoverStrategy) Name() string {
+	return "ema_crossover"
+}
+
+// GenerateTradingSignal generates a signal from the latest EMA crossover
+func (s *EMACrossoverStrategy) GenerateTradingSignal(ctx context.Context, input *models.SignalInput) (*models.TradingSignal, error) {
+	ind := input.Indicators
+	if ind == nil || ind.EMA5 == nil || ind.EMA20 == nil || ind.ATR14 == nil {
+		return ruleWait(s, input, errNotEnoughCandles.Error()), nil
+	}
+
+	var direction string
+	switch ind.EMACrossover {
+	case "bullish":
+		direction = "BUY"
+	case "bearish":
+		direction = "SELL"
+	default:
+		return ruleWait(s, input, fmt.Sprintf("No EMA %d/%d crossover in the last %d candles (EMA%d %.2f, EMA%d %.2f)",
+			indicators.FastEMAPeriod, indicators.SlowEMAPeriod, indicators.CrossoverLookback,
+			indicators.FastEMAPeriod, *ind.EMA5, indicators.SlowEMAPeriod, *ind.EMA20)), nil
+	}
+
+	confidence := ruleBaseConfidence + 5
+	if ind.RSI14 != nil && ((direction == "BUY" && *ind.RSI14 >= 50 && *ind.RSI14 < rsiOverbought) ||
+		(direction == "SELL" && *ind.RSI14 <= 50 && *ind.RSI14 > rsiOversold)) {
+		confidence += 10
+	}
+	if ind.MACDHistogram != nil && ((direction == "BUY" && *ind.MACDHistogram > 0) || (direction == "SELL" && *ind.MACDHistogram < 0)) {
+		confidence += 10
+	}
+
+	reason := fmt.Sprintf("EMA%d crossed %s EMA%d within the last %d candles (EMA%d %.2f, EMA%d %.2f)",
+		indicators.FastEMAPeriod, crossDirection(direction), indicators.SlowEMAPeriod,
+		indicators.CrossoverLookback, indicators.FastEMAPeriod, *ind.EMA5, indicators.SlowEMAPeriod, *ind.EMA20)
+	return ruleSignal(s, input, direction, lastClose(input), *ind.ATR14*ruleStopATRMultiple, confidence, reason), nil
+}
+
+// RSIMeanReversionStrategy buys oversold and sells overbought conditions, expecting a return to the mean
+type RSIMeanReversionStrategy struct{}
+
+// Name returns the strategy name
+func (s *RSIMeanReversionStrategy) Name() string {
+	return "rsi_mean_reversion"
+}
+
+// GenerateTradingSignal generates a signal when RSI is outside the oversold/overbought band
+func (s *RSIMeanReversionStrategy) GenerateTradingSignal(ctx context.Context, input *models.SignalInput) (*models.TradingSignal, error) {
+	ind := input.Indicators
+	if ind == nil || ind.RSI14 == nil || ind.ATR14 == nil {
+		return ruleWait(s, input, errNotEnoughCandles.Error()), nil
+	}
+
+	rsi := *ind.RSI14
+	var direction, condition string
+	var extremity float64
+	switch {
+	case rsi < rsiOversold:
+		direction, condition, extremity = "BUY", "oversold", rsiOversold-rsi
+	case rsi > rsiOverbought:
+		direction, condition, extremity = "SELL", "overbought", rsi-rsiOverbought
+	default:
+		return ruleWait(s, input, fmt.Sprintf("RSI %.1f is between %.0f and %.0f", rsi, rsiOversold, rsiOverbought)), nil
+	}
+
+	// Deeper extremes and a close outside the Bollinger Bands make a reversion more likely
+	confidence := ruleBaseConfidence + int(extremity*1.5)
+	close := lastClose(input)
+	if (direction == "BUY" && ind.BollingerLower != nil && close < *ind.BollingerLower) ||
+		(direction == "SELL" && ind.BollingerUpper != nil && close > *ind.BollingerUpper) {
+		confidence += 10
+	}
+
+	reason := fmt.Sprintf("RSI %.1f is %s", rsi, condition)
+	return ruleSignal(s, input, direction, close, *ind.ATR14*ruleStopATRMultiple, confidence, reason), nil
+}
+
+// VWAPBreakoutStrategy trades a close crossing the session VWAP on above-average volume
+type VWAPBreakoutStrategy struct{}
+
+// Name returns the strategy name
+func (s *VWAPBreakoutStrategy) Name() string {
+	return "vwap_breakout"
+}
+
+// GenerateTradingSignal generates a signal when the last candle closes across VWAP with volume confirmation
+func (s *VWAPBreakoutStrategy) GenerateTradingSignal(ctx context.Context, input *models.SignalInput) (*models.TradingSignal, error) {
+	data := input.OHLCData
+	ind := input.Indicators
+	if len(data) < vwapVolumeLookback+1 || ind == nil || ind.ATR14 == nil {
+		return ruleWait(s, input, errNotEnoughCandles.Error()), nil
+	}
+
+	vwap := indicators.VWAP(data)
+	last, previous := data[len(data)-1], data[len(data)-2]
+	lastVWAP, previousVWAP := vwap[len(vwap)-1], vwap[len(vwap)-2]
+	if math.IsNaN(lastVWAP) || math.IsNaN(previousVWAP) {
+		return ruleWait(s, input, "VWAP is unavailable without volume"), nil
+	}
+
+	var direction string
+	switch {
+	case previous.Close <= previousVWAP && last.Close > lastVWAP:
+		direction = "BUY"
+	case previous.Close >= previousVWAP && last.Close < lastVWAP:
+		direction = "SELL"
+	default:
+		return ruleWait(s, input, fmt.Sprintf("No close across VWAP %.2f on the last candle", lastVWAP)), nil
+	}
+
+	var volumeSum float64
+	for _, candle := range data[len(data)-1-vwapVolumeLookback : len(data)-1] {
+		volumeSum += float64(candle.Volume)
+	}
+	volumeRatio := 0.0
+	if average := volumeSum / vwapVolumeLookback; average > 0 {
+		volumeRatio = float64(last.Volume) / average
+	}
+	if volumeRatio < vwapVolumeMultiple {
+		return ruleWait(s, input, fmt.Sprintf("Close crossed VWAP %.2f but volume is only %.1fx the %d-candle average (needs %.1fx)",
+			lastVWAP, volumeRatio, vwapVolumeLookback, vwapVolumeMultiple)), nil
+	}
+
+	// Stronger volume raises the confidence, and the stop sits beyond VWAP when that is tighter
+	confidence := ruleBaseConfidence + int(math.Min(volumeRatio-vwapVolumeMultiple, 2)*10)
+	stopDistance := math.Min(math.Abs(last.Close-lastVWAP)+*ind.ATR14*0.5, *ind.ATR14*ruleStopATRMultiple)
+
+	reason := fmt.Sprintf("Close %.2f crossed %s VWAP %.2f on %.1fx the %d-candle average volume",
+		last.Close, crossDirection(direction), lastVWAP, volumeRatio, vwapVolumeLookback)
+	return ruleSignal(s, input, direction, last.Close, stopDistance, confidence, reason), nil
+}
+
+// OpeningRangeBreakoutStrategy trades a close beyond the high or low of the session's opening
+// range, with the stop at the middle of the range. It needs intraday candles.
+type OpeningRangeBreakoutStrategy struct{}
+
+// Name returns the strategy name
+func (s *OpeningRangeBreakoutStrategy) Name() string {
+	return "opening_range_breakout"
+}
+
+// GenerateTradingSignal generates a signal when the last close breaks out of the opening range
+func (s *OpeningRangeBreakoutStrategy) GenerateTradingSignal(ctx context.Context, input *models.SignalInput) (*models.TradingSignal, error) {
+	if input.Interval == "1d" {
+		return ruleWait(s, input, "Opening range breakout needs intraday candles"), nil
+	}
+	data := input.OHLCData
+	if len(data) == 0 {
+		return ruleWait(s, input, errNotEnoughCandles.Error()), nil
+	}
+
+	// The session is the exchange-local day of the last candle
+	location := exchangeLocation(input.Symbol)
+	last := data[len(data)-1]
+	session := last.Timestamp.In(location).Format("2006-01-02")
+	start := len(data) - 1
+	for start > 0 && data[start-1].Timestamp.In(location).Format("2006-01-02") == session {
+		start--
+	}
+
+	rangeEnd := data[start].Timestamp.Add(openingRangeMinutes * time.Minute)
+	rangeHigh, rangeLow := math.Inf(-1), math.Inf(1)
+	closed := false
+	for _, candle := range data[start:] {
+		if !candle.Timestamp.Before(rangeEnd) {
+			closed = true
+			break
+		}
+		rangeHigh, rangeLow = math.Max(rangeHigh, candle.High), math.Min(rangeLow, candle.Low)
+	}
+	if !closed {
+		return ruleWait(s, input, fmt.Sprintf("The %d-minute opening range has not completed", openingRangeMinutes)), nil
+	}
+
+	var direction string
+	switch {
+	case last.Close > rangeHigh:
+		direction = "BUY"
+	case last.Close < rangeLow:
+		direction = "SELL"
+	default:
+		return ruleWait(s, input, fmt.Sprintf("Close %.2f is inside the opening range %.2f-%.2f", last.Close, rangeLow, rangeHigh)), nil
+	}
+
+	// The stop sits at the middle of the range; confidence rises when the trend agrees
+	middle := (rangeHigh + rangeLow) / 2
+	confidence := ruleBaseConfidence + 5
+	if ind := input.Indicators; ind != nil && ind.EMA20 != nil &&
+		((direction == "BUY" && last.Close > *ind.EMA20) || (direction == "SELL" && last.Close < *ind.EMA20)) {
+		confidence += 10
+	}
+
+	reason := fmt.Sprintf("Close %.2f broke %s the %d-minute opening range %.2f-%.2f",
+		last.Close, crossDirection(direction), openingRangeMinutes, rangeLow, rangeHigh)
+	return ruleSignal(s, input, direction, last.Close, math.Abs(last.Close-middle), confidence, reason), nil
+}
+
+// ruleSignal builds a BUY/SELL signal at the entry with the stop at the given distance and the
+// target at the minimum risk-reward ratio
+func ruleSignal(strategy Strategy, input *models.SignalInput, direction string, entry, stopDistance float64, confidence int, reason string) *models.TradingSignal {
+	if stopDistance <= 0 {
+		return ruleWait(strategy, input, reason+", but there is no room for a stop")
+	}
+
+	ratio := input.MinRiskReward
+	if ratio <= 0 {
+		ratio = 2
+	}
+
+	signal := &models.TradingSignal{
+		Signal:     direction,
+		BuyPrice:   entry,
+		Confidence: int(math.Min(float64(confidence), ruleMaxConfidence)),
+		Reason:     reason,
+		Model:      strategy.Name(),
+	}
+	if direction == "BUY" {
+		signal.StopLoss = entry - stopDistance
+		signal.TargetPrice = entry + stopDistance*ratio
+	} else {
+		signal.StopLoss = entry + stopDistance
+		signal.TargetPrice = entry - stopDistance*ratio
+	}
+	annotateSignal(signal, input)
+	return signal
+}
+
+// ruleWait builds a WAIT signal explaining why the strategy has no trade
+func ruleWait(strategy Strategy, input *models.SignalInput, reason string) *models.TradingSignal {
+	close := lastClose(input)
+	signal := &models.TradingSignal{
+		Signal:      "WAIT",
+		BuyPrice:    close,
+		TargetPrice: close,
+		StopLoss:    close,
+		Reason:      reason,
+		Model:       strategy.Name(),
+	}
+	annotateSignal(signal, input)
+	return signal
+}
+
+// crossDirection describes the direction a BUY or SELL signal's price crossed a level
+func crossDirection(direction string) string {
+	if direction == "BUY" {
+		return "above"
+	}
+	return "below"
+}
+
+// lastClose returns the close of the latest candle in the input
+func lastClose(input *models.SignalInput) float64 {
+	if len(input.OHLCData) == 0 {
+		return 0
+	}
+	return input.OHLCData[len(input.OHLCData)-1].Close
+}
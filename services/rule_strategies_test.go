@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/indicators"
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// ruleInput builds strategy inputs for a US symbol
+func ruleInput(interval string, candles []models.OHLCData) *models.SignalInput {
+	return &models.SignalInput{
+		Symbol:        models.Symbol{Code: "AAPL", Exchange: "US", Ticker: "AAPL"},
+		Interval:      interval,
+		OHLCData:      candles,
+		Indicators:    indicators.Compute(candles),
+		MinRiskReward: 2,
+	}
+}
+
+func TestRuleStrategies(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("America/New_York time zone unavailable: %v", err)
+	}
+	open := time.Date(2024, 3, 4, 9, 30, 0, 0, location)
+
+	// Thirty hourly candles falling two points each
+	var falling []models.OHLCData
+	for i := 0; i < 30; i++ {
+		price := 200 - 2*float64(i)
+		falling = append(falling, models.OHLCData{
+			Timestamp: open.Add(time.Duration(i-30) * time.Hour),
+			Open:      price, High: price + 0.5, Low: price - 2.5, Close: price - 2, Volume: 1000,
+		})
+	}
+
+	// A 5-minute session whose first 30 minutes trade between 100 and 102, then break out to 105
+	var breakout []models.OHLCData
+	for i := 0; i < 9; i++ {
+		candle := models.OHLCData{Timestamp: open.Add(time.Duration(i*5) * time.Minute), Open: 101, High: 102, Low: 100, Close: 101, Volume: 1000}
+		if i == 8 {
+			candle.High, candle.Close = 105.5, 105
+		}
+		breakout = append(breakout, candle)
+	}
+
+	tests := []struct {
+		name      string
+		strategy  Strategy
+		input     *models.SignalInput
+		direction string
+		stop      float64
+	}{
+		{"rsi oversold buys", &RSIMeanReversionStrategy{}, ruleInput("1h", falling), "BUY", 0},
+		{"opening range breakout buys with stop at mid-range", &OpeningRangeBreakoutStrategy{}, ruleInput("5m", breakout), "BUY", 101},
+		{"opening range needs intraday candles", &OpeningRangeBreakoutStrategy{}, ruleInput("1d", breakout), "WAIT", 0},
+		{"ema crossover waits without a cross", &EMACrossoverStrategy{}, ruleInput("1h", falling), "WAIT", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signal, err := tt.strategy.GenerateTradingSignal(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("GenerateTradingSignal returned error: %v", err)
+			}
+			if signal.Signal != tt.direction {
+				t.Fatalf("signal = %s, want %s (%s)", signal.Signal, tt.direction, signal.Reason)
+			}
+			if signal.Model != tt.strategy.Name() {
+				t.Errorf("model = %q, want %q", signal.Model, tt.strategy.Name())
+			}
+			if tt.direction == "WAIT" {
+				return
+			}
+			if tt.stop != 0 && signal.StopLoss != tt.stop {
+				t.Errorf("stop = %v, want %v", signal.StopLoss, tt.stop)
+			}
+			if _, _, ratio, err := calculateRiskReward(signal); err != nil || ratio < 2-1e-9 {
+				t.Errorf("risk-reward = %v (%v), want at least 2", ratio, err)
+			}
+		})
+	}
+}
+
+func TestStrategyRegistry(t *testing.T) {
+	registry := NewStrategyRegistry("ema_crossover", map[string]string{"bbca": "RSI_MEAN_REVERSION"})
+	for _, strategy := range RuleStrategies() {
+		registry.Register(strategy)
+	}
+	if err := registry.Validate(); err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		symbol models.Symbol
+		want   string
+	}{
+		{models.Symbol{Code: "BBCA", Exchange: "IDX", Ticker: "BBCA.JK"}, "rsi_mean_reversion"},
+		{models.Symbol{Code: "TLKM", Exchange: "IDX", Ticker: "TLKM.JK"}, "ema_crossover"},
+	} {
+		strategy, err := registry.StrategyFor(tt.symbol)
+		if err != nil || strategy.Name() != tt.want {
+			t.Errorf("StrategyFor(%s) = %v, %v, want %s", tt.symbol.Ticker, strategy, err, tt.want)
+		}
+	}
+
+	if err := NewStrategyRegistry("gemini", nil).Validate(); err == nil {
+		t.Error("Validate accepted an unregistered default strategy")
+	}
+}
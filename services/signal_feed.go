@@ -0,0 +1,193 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// Signal feed message types
+const (
+	FeedSubscribed = "subscribed"
+	FeedSignal     = "signal"
+	FeedError      = "error"
+)
+
+const (
+	signalFeedBacklog    = 100 // Recent signals kept for subscribers resuming after a reconnect
+	signalFeedBufferSize = 64  // Signals a subscriber may fall behind before it is dropped
+)
+
+// SignalFeed broadcasts every generated signal to its subscribers, each with its own filter.
+// It keeps the most recent signals so that a subscriber that reconnects can resume where it
+// left off. A nil feed discards signals.
+type SignalFeed struct {
+	subscribers map[*SignalSubscription]struct{}
+	recent      []models.SignalFeedMessage // Oldest first
+	seq         uint64
+	closed      bool
+	mutex       sync.Mutex
+}
+
+// SignalSubscription receives the feed's signals that match its filter. Its fields are
+// guarded by the feed's mutex.
+type SignalSubscription struct {
+	feed     *SignalFeed
+	filter   models.SignalFilter
+	messages chan models.SignalFeedMessage
+}
+
+// NewSignalFeed creates a signal feed without subscribers
+func NewSignalFeed() *SignalFeed {
+	return &SignalFeed{
+		subscribers: make(map[*SignalSubscription]struct{}),
+	}
+}
+
+// Publish numbers a signal and sends it to the subscribers whose filter matches, dropping any
+// subscriber whose buffer is full rather than holding up signal generation. Subscribers get a
+// copy, so that recording the signal's dispatch afterwards does not race with them.
+func (f *SignalFeed) Publish(signal *models.TradingSignal) {
+	if f == nil {
+		return
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.closed {
+		return
+	}
+	copied := *signal
+	signal = &copied
+	f.seq++
+	message := models.SignalFeedMessage{Type: FeedSignal, Seq: f.seq, Signal: signal, Time: time.Now()}
+	f.recent = append(f.recent, message)
+	if len(f.recent) > signalFeedBacklog {
+		f.recent = slices.Delete(f.recent, 0, len(f.recent)-signalFeedBacklog)
+	}
+
+	for subscription := range f.subscribers {
+		if !matchesSignalFilter(subscription.filter, signal) {
+			continue
+		}
+		select {
+		case subscription.messages <- message:
+		default:
+			f.unsubscribe(subscription)
+		}
+	}
+}
+
+// Subscribe returns a subscription to the signals matching filter. A positive lastSeq replays
+// the kept signals published after it; when lastSeq is ahead of the feed, which restarted
+// since, all kept signals are replayed. The subscription's channel closes when it falls too
+// far behind or the feed closes.
+func (f *SignalFeed) Subscribe(filter models.SignalFilter, lastSeq uint64) *SignalSubscription {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var replay []models.SignalFeedMessage
+	if lastSeq > 0 {
+		for _, message := range f.recent {
+			if (message.Seq > lastSeq || lastSeq > f.seq) && matchesSignalFilter(filter, message.Signal) {
+				replay = append(replay, message)
+			}
+		}
+	}
+
+	subscription := &SignalSubscription{
+		feed:     f,
+		filter:   filter,
+		messages: make(chan models.SignalFeedMessage, signalFeedBufferSize+len(replay)),
+	}
+	for _, message := range replay {
+		subscription.messages <- message
+	}
+	if f.closed {
+		close(subscription.messages)
+		return subscription
+	}
+	f.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+// Close closes every subscription and stops publishing
+func (f *SignalFeed) Close() {
+	if f == nil {
+		return
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.closed = true
+	for subscription := range f.subscribers {
+		f.unsubscribe(subscription)
+	}
+}
+
+// unsubscribe removes and closes a subscription with the lock held
+func (f *SignalFeed) unsubscribe(subscription *SignalSubscription) {
+	if _, exists := f.subscribers[subscription]; exists {
+		delete(f.subscribers, subscription)
+		close(subscription.messages)
+	}
+}
+
+// Messages returns the channel of signals matching the subscription's filter
+func (s *SignalSubscription) Messages() <-chan models.SignalFeedMessage {
+	return s.messages
+}
+
+// SetFilter replaces the subscription's filter for the signals published from now on
+func (s *SignalSubscription) SetFilter(filter models.SignalFilter) {
+	s.feed.mutex.Lock()
+	defer s.feed.mutex.Unlock()
+	s.filter = filter
+}
+
+// Close stops the subscription and closes its channel
+func (s *SignalSubscription) Close() {
+	s.feed.mutex.Lock()
+	defer s.feed.mutex.Unlock()
+	s.feed.unsubscribe(s)
+}
+
+// normalizeSignalFilter checks a signal feed filter and normalizes its symbols to tickers and its
+// signal types to upper case
+func normalizeSignalFilter(resolver *SymbolResolver, filter models.SignalFilter) (models.SignalFilter, error) {
+	normalized := models.SignalFilter{MinConfidence: filter.MinConfidence}
+	if filter.MinConfidence < 0 || filter.MinConfidence > 100 {
+		return normalized, fmt.Errorf("min_confidence must be between 0 and 100, got %d", filter.MinConfidence)
+	}
+
+	for _, raw := range filter.Symbols {
+		symbol, err := resolver.Normalize(raw)
+		if err != nil {
+			return normalized, err
+		}
+		normalized.Symbols = append(normalized.Symbols, symbol.Ticker)
+	}
+	for _, raw := range filter.Signals {
+		signalType := strings.ToUpper(strings.TrimSpace(raw))
+		if signalType != "BUY" && signalType != "SELL" && signalType != "WAIT" {
+			return normalized, fmt.Errorf("invalid signal type %q (expected BUY, SELL or WAIT)", raw)
+		}
+		normalized.Signals = append(normalized.Signals, signalType)
+	}
+	return normalized, nil
+}
+
+// matchesSignalFilter reports whether a signal passes a normalized filter
+func matchesSignalFilter(filter models.SignalFilter, signal *models.TradingSignal) bool {
+	if len(filter.Symbols) > 0 && !slices.Contains(filter.Symbols, signalSymbol(signal).Ticker) {
+		return false
+	}
+	if len(filter.Signals) > 0 && !slices.Contains(filter.Signals, strings.ToUpper(signal.Signal)) {
+		return false
+	}
+	return signal.Confidence >= filter.MinConfidence
+}
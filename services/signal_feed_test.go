@@ -0,0 +1,83 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// feedSignals drains the messages waiting on a subscription and returns their symbols
+func feedSignals(subscription *SignalSubscription) []string {
+	var symbols []string
+	for {
+		select {
+		case message, ok := <-subscription.Messages():
+			if !ok {
+				return symbols
+			}
+			symbols = append(symbols, message.Signal.StockSymbol)
+		default:
+			return symbols
+		}
+	}
+}
+
+func TestSignalFeed(t *testing.T) {
+	resolver := NewSymbolResolver([]string{"BBCA.JK", "TLKM.JK"}, false)
+	filter, err := normalizeSignalFilter(resolver, models.SignalFilter{Symbols: []string{"bbca"}, Signals: []string{"buy"}, MinConfidence: 70})
+	if err != nil {
+		t.Fatalf("normalizeSignalFilter returned error: %v", err)
+	}
+	if filter.Symbols[0] != "BBCA.JK" || filter.Signals[0] != "BUY" {
+		t.Errorf("normalized filter = %+v", filter)
+	}
+	for _, invalid := range []models.SignalFilter{{Signals: []string{"HOLD"}}, {MinConfidence: 101}, {Symbols: []string{"XXXX"}}} {
+		if _, err := normalizeSignalFilter(resolver, invalid); err == nil {
+			t.Errorf("normalizeSignalFilter accepted %+v", invalid)
+		}
+	}
+
+	feed := NewSignalFeed()
+	filtered := feed.Subscribe(filter, 0)
+	all := feed.Subscribe(models.SignalFilter{}, 0)
+
+	signals := []*models.TradingSignal{
+		{StockSymbol: "BBCA", Exchange: "IDX", Signal: "BUY", Confidence: 80},
+		{StockSymbol: "BBCA", Exchange: "IDX", Signal: "BUY", Confidence: 60},
+		{StockSymbol: "TLKM", Exchange: "IDX", Signal: "BUY", Confidence: 90},
+		{StockSymbol: "BBCA", Exchange: "IDX", Signal: "SELL", Confidence: 90},
+	}
+	for _, signal := range signals {
+		feed.Publish(signal)
+	}
+
+	if got := feedSignals(filtered); len(got) != 1 {
+		t.Errorf("filtered subscriber received %v, want only the confident BBCA buy", got)
+	}
+	if got := feedSignals(all); len(got) != len(signals) {
+		t.Errorf("unfiltered subscriber received %d signals, want %d", len(got), len(signals))
+	}
+
+	// Reconnecting after seq 2 replays the signals published since
+	resumed := feed.Subscribe(models.SignalFilter{}, 2)
+	if got := feedSignals(resumed); len(got) != 2 || got[0] != "TLKM" {
+		t.Errorf("resumed subscriber replayed %v, want TLKM and BBCA", got)
+	}
+
+	// A subscriber that falls behind is dropped instead of blocking the feed
+	for i := 0; i <= signalFeedBufferSize; i++ {
+		feed.Publish(signals[0])
+	}
+	if _, ok := <-all.Messages(); !ok {
+		t.Fatal("slow subscriber's channel closed before its buffered signals")
+	}
+	if got := feedSignals(all); len(got) != signalFeedBufferSize-1 {
+		t.Errorf("slow subscriber received %d more signals, want %d before being dropped", len(got), signalFeedBufferSize-1)
+	}
+
+	idle := feed.Subscribe(models.SignalFilter{}, 0)
+	feed.Close()
+	if _, ok := <-idle.Messages(); ok {
+		t.Error("subscription stayed open after the feed closed")
+	}
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// signalResponseSchema is the JSON schema of a model's signal response. It is sent to
+// providers that support structured output and included in the prompt for the others.
+var signalResponseSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"signal":       map[string]any{"type": "string", "enum": []string{"BUY", "SELL", "WAIT"}},
+		"buy_price":    map[string]any{"type": "number", "minimum": 0},
+		"target_price": map[string]any{"type": "number", "minimum": 0},
+		"stop_loss":    map[string]any{"type": "number", "minimum": 0},
+		"confidence":   map[string]any{"type": "integer", "minimum": 0, "maximum": 100},
+		"reason":       map[string]any{"type": "string"},
+		"ohlcv_analysis": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"open":        map[string]any{"type": "number", "minimum": 0},
+				"high":        map[string]any{"type": "number", "minimum": 0},
+				"low":         map[string]any{"type": "number", "minimum": 0},
+				"close":       map[string]any{"type": "number", "minimum": 0},
+				"volume":      map[string]any{"type": "integer", "minimum": 0},
+				"explanation": map[string]any{"type": "string"},
+			},
+			"required":             []string{"open", "high", "low", "close", "volume", "explanation"},
+			"additionalProperties": false,
+		},
+	},
+	"required":             []string{"signal", "buy_price", "target_price", "stop_loss", "confidence", "reason", "ohlcv_analysis"},
+	"additionalProperties": false,
+}
+
+// SignalSchemaError is returned when a model response does not match the signal schema
+type SignalSchemaError struct {
+	Problems []string
+}
+
+// Error returns the problems found in the response
+func (e *SignalSchemaError) Error() string {
+	return "response does not match the signal schema: " + strings.Join(e.Problems, "; ")
+}
+
+// signalResponse mirrors the signal schema with pointers so that missing fields can be told apart from zeros
+type signalResponse struct {
+	Signal        *string        `json:"signal"`
+	BuyPrice      *float64       `json:"buy_price"`
+	TargetPrice   *float64       `json:"target_price"`
+	StopLoss      *float64       `json:"stop_loss"`
+	Confidence    *float64       `json:"confidence"`
+	Reason        *string        `json:"reason"`
+	OHLCVAnalysis *ohlcvResponse `json:"ohlcv_analysis"`
+}
+
+// ohlcvResponse mirrors the ohlcv_analysis object of the signal schema
+type ohlcvResponse struct {
+	Open        *float64 `json:"open"`
+	High        *float64 `json:"high"`
+	Low         *float64 `json:"low"`
+	Close       *float64 `json:"close"`
+	Volume      *float64 `json:"volume"`
+	Explanation *string  `json:"explanation"`
+}
+
+// parseSignalResponse extracts the JSON object from a model response and validates it against
+// the signal schema. Every problem found is reported in a *SignalSchemaError.
+func parseSignalResponse(text string) (*models.TradingSignal, error) {
+	// Extract JSON from the response (in case there's extra text or a code fence)
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start == -1 || end < start {
+		return nil, &SignalSchemaError{Problems: []string{"no JSON object found in the response"}}
+	}
+
+	var response signalResponse
+	if err := json.Unmarshal([]byte(text[start:end+1]), &response); err != nil {
+		return nil, &SignalSchemaError{Problems: []string{fmt.Sprintf("invalid JSON: %v", err)}}
+	}
+
+	var problems []string
+	if response.Signal == nil {
+		problems = append(problems, "signal is required")
+	} else if *response.Signal != "BUY" && *response.Signal != "SELL" && *response.Signal != "WAIT" {
+		problems = append(problems, fmt.Sprintf("signal must be exactly \"BUY\", \"SELL\" or \"WAIT\", got %q", *response.Signal))
+	}
+	actionable := response.Signal != nil && *response.Signal != "WAIT"
+
+	prices := []struct {
+		name  string
+		value *float64
+	}{
+		{"buy_price", response.BuyPrice},
+		{"target_price", response.TargetPrice},
+		{"stop_loss", response.StopLoss},
+	}
+	for _, price := range prices {
+		switch {
+		case price.value == nil:
+			problems = append(problems, price.name+" is required")
+		case *price.value < 0:
+			problems = append(problems, fmt.Sprintf("%s must be a non-negative number, got %v", price.name, *price.value))
+		case actionable && *price.value == 0:
+			problems = append(problems, fmt.Sprintf("%s must be positive for a %s signal", price.name, *response.Signal))
+		}
+	}
+
+	switch {
+	case response.Confidence == nil:
+		problems = append(problems, "confidence is required")
+	case *response.Confidence < 0 || *response.Confidence > 100 || *response.Confidence != math.Trunc(*response.Confidence):
+		problems = append(problems, fmt.Sprintf("confidence must be an integer from 0 to 100, got %v", *response.Confidence))
+	}
+
+	if response.Reason == nil || strings.TrimSpace(*response.Reason) == "" {
+		problems = append(problems, "reason is required and must not be empty")
+	}
+
+	var analysis *models.OHLCVAnalysis
+	if response.OHLCVAnalysis == nil {
+		problems = append(problems, "ohlcv_analysis is required")
+	} else {
+		var analysisProblems []string
+		analysis, analysisProblems = validateOHLCVResponse(response.OHLCVAnalysis)
+		problems = append(problems, analysisProblems...)
+	}
+
+	if len(problems) > 0 {
+		return nil, &SignalSchemaError{Problems: problems}
+	}
+
+	return &models.TradingSignal{
+		Signal:        *response.Signal,
+		BuyPrice:      *response.BuyPrice,
+		TargetPrice:   *response.TargetPrice,
+		StopLoss:      *response.StopLoss,
+		Confidence:    int(*response.Confidence),
+		Reason:        *response.Reason,
+		OHLCVAnalysis: analysis,
+	}, nil
+}
+
+// validateOHLCVResponse validates the ohlcv_analysis object and converts it to the model type
+func validateOHLCVResponse(response *ohlcvResponse) (*models.OHLCVAnalysis, []string) {
+	var problems []string
+	values := []struct {
+		name  string
+		value *float64
+	}{
+		{"open", response.Open},
+		{"high", response.High},
+		{"low", response.Low},
+		{"close", response.Close},
+		{"volume", response.Volume},
+	}
+	for _, v := range values {
+		switch {
+		case v.value == nil:
+			problems = append(problems, "ohlcv_analysis."+v.name+" is required")
+		case *v.value < 0:
+			problems = append(problems, fmt.Sprintf("ohlcv_analysis.%s must be a non-negative number, got %v", v.name, *v.value))
+		}
+	}
+	if response.Explanation == nil {
+		problems = append(problems, "ohlcv_analysis.explanation is required")
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+
+	return &models.OHLCVAnalysis{
+		Open:        *response.Open,
+		High:        *response.High,
+		Low:         *response.Low,
+		Close:       *response.Close,
+		Volume:      int64(math.Round(*response.Volume)),
+		Explanation: *response.Explanation,
+	}, nil
+}
+
+// signalSchemaJSON returns the signal schema as indented JSON for the prompt
+func signalSchemaJSON() string {
+	schema, _ := json.MarshalIndent(signalResponseSchema, "", "  ")
+	return string(schema)
+}
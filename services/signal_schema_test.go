@@ -0,0 +1,55 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseSignalResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		problems []string
+	}{
+		{"valid inside a code fence", "```json\n" + validSignalResponse + "\n```", nil},
+		{"wait with zero prices", `{"signal": "WAIT", "buy_price": 0, "target_price": 0, "stop_loss": 0, "confidence": 40, "reason": "no setup",
+			"ohlcv_analysis": {"open": 1, "high": 1, "low": 1, "close": 1, "volume": 0, "explanation": ""}}`, nil},
+		{"no JSON", "I cannot help with that", []string{"no JSON object"}},
+		{"malformed JSON", `{"signal": "BUY",}`, []string{"invalid JSON"}},
+		{"wrong case and hold", strings.Replace(validSignalResponse, `"BUY"`, `"HOLD"`, 1), []string{`got "HOLD"`}},
+		{"missing fields", `{"signal": "SELL", "buy_price": 100, "confidence": 101.5}`, []string{
+			"target_price is required", "stop_loss is required", "confidence must be an integer", "reason is required", "ohlcv_analysis is required",
+		}},
+		{"zero prices on a buy", strings.Replace(validSignalResponse, `"stop_loss": 1025`, `"stop_loss": 0`, 1), []string{"stop_loss must be positive"}},
+		{"negative volume", strings.Replace(validSignalResponse, `"volume": 1000`, `"volume": -1`, 1), []string{"ohlcv_analysis.volume must be a non-negative number"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signal, err := parseSignalResponse(tt.response)
+			if len(tt.problems) == 0 {
+				if err != nil {
+					t.Fatalf("parseSignalResponse returned error: %v", err)
+				}
+				if signal.OHLCVAnalysis == nil {
+					t.Error("ohlcv analysis was not parsed")
+				}
+				return
+			}
+
+			var schemaErr *SignalSchemaError
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("error = %v, want a *SignalSchemaError", err)
+			}
+			if len(schemaErr.Problems) != len(tt.problems) {
+				t.Errorf("problems = %q, want %d", schemaErr.Problems, len(tt.problems))
+			}
+			for _, want := range tt.problems {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,409 @@
+package services
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// ErrRecordNotFound is returned when a stored signal or summary does not exist
+	ErrRecordNotFound = errors.New("record not found")
+	// ErrSignalHistoryDisabled is returned when the signal history is queried while disabled
+	ErrSignalHistoryDisabled = errors.New("signal history is disabled")
+)
+
+const (
+	// DefaultQueryLimit is the number of records returned when a query has no limit
+	DefaultQueryLimit = 100
+	// MaxQueryLimit is the largest number of records returned by one query
+	MaxQueryLimit = 1000
+)
+
+var (
+	signalsBucket   = []byte("signals")
+	summariesBucket = []byte("summaries")
+	outcomesBucket  = []byte("outcomes")
+)
+
+// SignalStore persists generated signals and bulk analysis summaries
+type SignalStore interface {
+	// SaveSignal stores a signal record and assigns its ID and creation time
+	SaveSignal(record *models.SignalRecord) error
+	// GetSignal returns the signal record with the given ID
+	GetSignal(id string) (*models.SignalRecord, error)
+	// QuerySignals returns the signal records matching the query, newest first
+	QuerySignals(query models.SignalQuery) ([]*models.SignalRecord, error)
+	// SaveSummary stores a summary record and assigns its ID and creation time
+	SaveSummary(record *models.SummaryRecord) error
+	// QuerySummaries returns the summary records in the query's date range, newest first
+	QuerySummaries(query models.SignalQuery) ([]*models.SummaryRecord, error)
+	// SaveOutcome stores or replaces the outcome of a stored signal
+	SaveOutcome(outcome *models.SignalOutcome) error
+	// GetOutcome returns the outcome of a stored signal
+	GetOutcome(signalID string) (*models.SignalOutcome, error)
+	// QueryOutcomes returns the outcomes of signals matching the query, newest first
+	QueryOutcomes(query models.SignalQuery) ([]*models.SignalOutcome, error)
+	// Close releases the store
+	Close() error
+}
+
+// BoltSignalStore is a SignalStore backed by an embedded BoltDB file.
+// Records are keyed by an increasing sequence so iteration follows insertion order.
+type BoltSignalStore struct {
+	db *bolt.DB
+}
+
+// NewBoltSignalStore opens (or creates) the BoltDB file at path
+func NewBoltSignalStore(path string) (*BoltSignalStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create signal store directory: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open signal store %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{signalsBucket, summariesBucket, outcomesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize signal store: %w", err)
+	}
+
+	return &BoltSignalStore{db: db}, nil
+}
+
+// SaveSignal stores a signal record and assigns its ID
+func (s *BoltSignalStore) SaveSignal(record *models.SignalRecord) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(signalsBucket)
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		// Writers are serialized, so creation times follow the key order
+		record.ID = formatRecordID("sig", seq)
+		record.CreatedAt = time.Now()
+		if record.Signal != nil {
+			record.Signal.ID = record.ID
+		}
+		return putJSON(bucket, seq, record)
+	})
+}
+
+// GetSignal returns the signal record with the given ID
+func (s *BoltSignalStore) GetSignal(id string) (*models.SignalRecord, error) {
+	seq, err := parseRecordID("sig", id)
+	if err != nil {
+		return nil, err
+	}
+
+	var record models.SignalRecord
+	err = s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(signalsBucket).Get(sequenceKey(seq))
+		if data == nil {
+			return fmt.Errorf("%w: signal %s", ErrRecordNotFound, id)
+		}
+		return json.Unmarshal(data, &record)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// QuerySignals returns the signal records matching the query, newest first
+func (s *BoltSignalStore) QuerySignals(query models.SignalQuery) ([]*models.SignalRecord, error) {
+	limit := queryLimit(query.Limit)
+	symbol := strings.ToUpper(query.Symbol)
+	signalType := strings.ToUpper(query.SignalType)
+	before, err := queryBefore(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*models.SignalRecord
+	err = s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(signalsBucket).Cursor()
+		for key, data := seekBefore(cursor, before); key != nil && len(records) < limit; key, data = cursor.Prev() {
+			var record models.SignalRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return err
+			}
+			// Records are in insertion order, so everything before From is older
+			if !query.From.IsZero() && record.CreatedAt.Before(query.From) {
+				break
+			}
+			if !query.To.IsZero() && record.CreatedAt.After(query.To) {
+				continue
+			}
+			if symbol != "" && record.Symbol != symbol && record.Ticker != symbol {
+				continue
+			}
+			if signalType != "" && record.SignalType != signalType {
+				continue
+			}
+			if !matchesSignalTypes(query.SignalTypes, record.SignalType) {
+				continue
+			}
+			if record.Confidence < query.MinConfidence {
+				continue
+			}
+			if query.MaxConfidence > 0 && record.Confidence > query.MaxConfidence {
+				continue
+			}
+			records = append(records, &record)
+		}
+		return nil
+	})
+	return records, err
+}
+
+// SaveSummary stores a summary record and assigns its ID
+func (s *BoltSignalStore) SaveSummary(record *models.SummaryRecord) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(summariesBucket)
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		record.ID = formatRecordID("sum", seq)
+		record.CreatedAt = time.Now()
+		return putJSON(bucket, seq, record)
+	})
+}
+
+// QuerySummaries returns the summary records in the query's date range, newest first
+func (s *BoltSignalStore) QuerySummaries(query models.SignalQuery) ([]*models.SummaryRecord, error) {
+	limit := queryLimit(query.Limit)
+
+	var records []*models.SummaryRecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(summariesBucket).Cursor()
+		for key, data := cursor.Last(); key != nil && len(records) < limit; key, data = cursor.Prev() {
+			var record models.SummaryRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return err
+			}
+			if !query.From.IsZero() && record.CreatedAt.Before(query.From) {
+				break
+			}
+			if !query.To.IsZero() && record.CreatedAt.After(query.To) {
+				continue
+			}
+			records = append(records, &record)
+		}
+		return nil
+	})
+	return records, err
+}
+
+// SaveOutcome stores or replaces the outcome of a stored signal, keyed by the signal's sequence
+func (s *BoltSignalStore) SaveOutcome(outcome *models.SignalOutcome) error {
+	seq, err := parseRecordID("sig", outcome.SignalID)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx.Bucket(outcomesBucket), seq, outcome)
+	})
+}
+
+// GetOutcome returns the outcome of a stored signal
+func (s *BoltSignalStore) GetOutcome(signalID string) (*models.SignalOutcome, error) {
+	seq, err := parseRecordID("sig", signalID)
+	if err != nil {
+		return nil, err
+	}
+
+	var outcome models.SignalOutcome
+	err = s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(outcomesBucket).Get(sequenceKey(seq))
+		if data == nil {
+			return fmt.Errorf("%w: no outcome for signal %s", ErrRecordNotFound, signalID)
+		}
+		return json.Unmarshal(data, &outcome)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &outcome, nil
+}
+
+// QueryOutcomes returns the outcomes of signals matching the query, newest first.
+// The date range applies to the signals' generation time.
+func (s *BoltSignalStore) QueryOutcomes(query models.SignalQuery) ([]*models.SignalOutcome, error) {
+	limit := queryLimit(query.Limit)
+	symbol := strings.ToUpper(query.Symbol)
+	signalType := strings.ToUpper(query.SignalType)
+	if idx := strings.LastIndex(symbol, "."); idx != -1 {
+		symbol = symbol[:idx]
+	}
+	before, err := queryBefore(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var outcomes []*models.SignalOutcome
+	err = s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(outcomesBucket).Cursor()
+		for key, data := seekBefore(cursor, before); key != nil && len(outcomes) < limit; key, data = cursor.Prev() {
+			var outcome models.SignalOutcome
+			if err := json.Unmarshal(data, &outcome); err != nil {
+				return err
+			}
+			if !query.From.IsZero() && outcome.GeneratedAt.Before(query.From) {
+				continue
+			}
+			if !query.To.IsZero() && outcome.GeneratedAt.After(query.To) {
+				continue
+			}
+			if symbol != "" && outcome.Symbol != symbol {
+				continue
+			}
+			if signalType != "" && outcome.SignalType != signalType {
+				continue
+			}
+			if !matchesSignalTypes(query.SignalTypes, outcome.SignalType) {
+				continue
+			}
+			outcomes = append(outcomes, &outcome)
+		}
+		return nil
+	})
+	return outcomes, err
+}
+
+// Close closes the BoltDB file
+func (s *BoltSignalStore) Close() error {
+	return s.db.Close()
+}
+
+// putJSON stores a value as JSON under a sequence key
+func putJSON(bucket *bolt.Bucket, seq uint64, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(sequenceKey(seq), data)
+}
+
+// sequenceKey encodes a sequence number as a big-endian key so keys sort numerically
+func sequenceKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
+
+// formatRecordID builds a record ID such as "sig_42"
+func formatRecordID(prefix string, seq uint64) string {
+	return fmt.Sprintf("%s_%d", prefix, seq)
+}
+
+// parseRecordID extracts the sequence number from a record ID
+func parseRecordID(prefix, id string) (uint64, error) {
+	seq, err := strconv.ParseUint(strings.TrimPrefix(id, prefix+"_"), 10, 64)
+	if err != nil || !strings.HasPrefix(id, prefix+"_") {
+		return 0, fmt.Errorf("%w: invalid id %s", ErrRecordNotFound, id)
+	}
+	return seq, nil
+}
+
+// QueryAllSignals returns every stored signal matching the query, newest first, paging through
+// the store MaxQueryLimit records at a time; the query's limit is ignored
+func QueryAllSignals(store SignalStore, query models.SignalQuery) ([]*models.SignalRecord, error) {
+	query.Limit = MaxQueryLimit
+	var records []*models.SignalRecord
+	for {
+		page, err := store.QuerySignals(query)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, page...)
+		if len(page) < MaxQueryLimit {
+			return records, nil
+		}
+		query.BeforeID = page[len(page)-1].ID
+	}
+}
+
+// QueryAllOutcomes returns every stored outcome matching the query, newest first, paging through
+// the store MaxQueryLimit records at a time; the query's limit is ignored
+func QueryAllOutcomes(store SignalStore, query models.SignalQuery) ([]*models.SignalOutcome, error) {
+	query.Limit = MaxQueryLimit
+	var outcomes []*models.SignalOutcome
+	for {
+		page, err := store.QueryOutcomes(query)
+		if err != nil {
+			return nil, err
+		}
+		outcomes = append(outcomes, page...)
+		if len(page) < MaxQueryLimit {
+			return outcomes, nil
+		}
+		query.BeforeID = page[len(page)-1].SignalID
+	}
+}
+
+// queryBefore returns the sequence of the query's BeforeID, or zero when it is not set
+func queryBefore(query models.SignalQuery) (uint64, error) {
+	if query.BeforeID == "" {
+		return 0, nil
+	}
+	return parseRecordID("sig", query.BeforeID)
+}
+
+// seekBefore positions a cursor at the newest key, or at the newest key below the sequence
+// when it is positive
+func seekBefore(cursor *bolt.Cursor, before uint64) ([]byte, []byte) {
+	if before == 0 {
+		return cursor.Last()
+	}
+	if key, _ := cursor.Seek(sequenceKey(before)); key == nil {
+		return cursor.Last()
+	}
+	return cursor.Prev()
+}
+
+// matchesSignalTypes reports whether a signal type is one of types, or types is empty
+func matchesSignalTypes(types []string, signalType string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if strings.EqualFold(t, signalType) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryLimit applies the default and maximum to a requested limit
+func queryLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultQueryLimit
+	}
+	if limit > MaxQueryLimit {
+		return MaxQueryLimit
+	}
+	return limit
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+func TestBoltSignalStoreQuerySignals(t *testing.T) {
+	store, err := NewBoltSignalStore(filepath.Join(t.TempDir(), "signals.db"))
+	if err != nil {
+		t.Fatalf("NewBoltSignalStore returned error: %v", err)
+	}
+	defer store.Close()
+
+	for _, record := range []*models.SignalRecord{
+		{Symbol: "BBCA", Ticker: "BBCA.JK", SignalType: "BUY", Confidence: 80, Signal: &models.TradingSignal{Signal: "BUY"}},
+		{Symbol: "TLKM", Ticker: "TLKM.JK", SignalType: "WAIT", Confidence: 50, Signal: &models.TradingSignal{Signal: "WAIT"}},
+		{Symbol: "BBCA", Ticker: "BBCA.JK", SignalType: "SELL", Confidence: 65, Signal: &models.TradingSignal{Signal: "SELL"}},
+	} {
+		if err := store.SaveSignal(record); err != nil {
+			t.Fatalf("SaveSignal returned error: %v", err)
+		}
+		if record.ID == "" || record.Signal.ID != record.ID {
+			t.Fatalf("SaveSignal did not assign the ID to the record and signal: %q %q", record.ID, record.Signal.ID)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query models.SignalQuery
+		want  []string
+	}{
+		{"all newest first", models.SignalQuery{}, []string{"sig_3", "sig_2", "sig_1"}},
+		{"by symbol", models.SignalQuery{Symbol: "bbca"}, []string{"sig_3", "sig_1"}},
+		{"by ticker and type", models.SignalQuery{Symbol: "BBCA.JK", SignalType: "buy"}, []string{"sig_1"}},
+		{"by confidence", models.SignalQuery{MinConfidence: 60, MaxConfidence: 70}, []string{"sig_3"}},
+		{"limit", models.SignalQuery{Limit: 1}, []string{"sig_3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := store.QuerySignals(tt.query)
+			if err != nil {
+				t.Fatalf("QuerySignals returned error: %v", err)
+			}
+			var got []string
+			for _, record := range records {
+				got = append(got, record.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("QuerySignals = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("QuerySignals = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	if _, err := store.GetSignal("sig_42"); !errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("GetSignal error = %v, want ErrRecordNotFound", err)
+	}
+}
+
+func TestQueryAllBeyondMaxQueryLimit(t *testing.T) {
+	store, err := NewBoltSignalStore(filepath.Join(t.TempDir(), "signals.db"))
+	if err != nil {
+		t.Fatalf("NewBoltSignalStore returned error: %v", err)
+	}
+	defer store.Close()
+
+	// One old BUY buried under a full query's worth of newer WAITs, then more BUYs with
+	// outcomes than one query returns
+	records := []*models.SignalRecord{{Symbol: "BBCA", SignalType: "BUY"}}
+	for i := 0; i < MaxQueryLimit+5; i++ {
+		records = append(records, &models.SignalRecord{Symbol: "TLKM", SignalType: "WAIT"})
+	}
+	for i := 0; i < MaxQueryLimit+1; i++ {
+		records = append(records, &models.SignalRecord{Symbol: "ASII", SignalType: "SELL"})
+	}
+	for _, record := range records {
+		if err := store.SaveSignal(record); err != nil {
+			t.Fatalf("SaveSignal returned error: %v", err)
+		}
+		if record.SignalType != "WAIT" {
+			if err := store.SaveOutcome(&models.SignalOutcome{SignalID: record.ID, Symbol: record.Symbol, SignalType: record.SignalType}); err != nil {
+				t.Fatalf("SaveOutcome returned error: %v", err)
+			}
+		}
+	}
+
+	actionable, err := QueryAllSignals(store, models.SignalQuery{SignalTypes: []string{"BUY", "SELL"}})
+	if err != nil {
+		t.Fatalf("QueryAllSignals returned error: %v", err)
+	}
+	if len(actionable) != MaxQueryLimit+2 || actionable[len(actionable)-1].ID != "sig_1" {
+		t.Errorf("QueryAllSignals returned %d BUY/SELL signals, want %d ending with sig_1", len(actionable), MaxQueryLimit+2)
+	}
+
+	all, err := QueryAllSignals(store, models.SignalQuery{})
+	if err != nil || len(all) != len(records) {
+		t.Errorf("QueryAllSignals returned %d signals and %v, want all %d", len(all), err, len(records))
+	}
+
+	outcomes, err := QueryAllOutcomes(store, models.SignalQuery{})
+	if err != nil {
+		t.Fatalf("QueryAllOutcomes returned error: %v", err)
+	}
+	if len(outcomes) != MaxQueryLimit+2 || outcomes[len(outcomes)-1].SignalID != "sig_1" {
+		t.Errorf("QueryAllOutcomes returned %d outcomes, want %d ending with sig_1", len(outcomes), MaxQueryLimit+2)
+	}
+}
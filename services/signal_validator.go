@@ -0,0 +1,114 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// Validation statuses recorded on signals
+const (
+	ValidationPassed        = "passed"
+	ValidationDowngraded    = "downgraded"
+	ValidationNotApplicable = "not_applicable"
+	ValidationRejected      = "rejected"
+)
+
+// SignalValidator checks price ordering and risk-reward of generated signals
+type SignalValidator struct {
+	minRiskReward float64
+}
+
+// NewSignalValidator creates a new signal validator enforcing the given minimum reward/risk ratio
+func NewSignalValidator(minRiskReward float64) *SignalValidator {
+	return &SignalValidator{
+		minRiskReward: minRiskReward,
+	}
+}
+
+// Validate computes the signal's risk-reward and checks that its prices are ordered correctly.
+// Failing BUY/SELL signals are downgraded to WAIT with the explanation appended to the reason.
+// The outcome is recorded on the signal and returned.
+func (v *SignalValidator) Validate(signal *models.TradingSignal) *models.SignalValidation {
+	validation := &models.SignalValidation{
+		Status:        ValidationPassed,
+		MinRiskReward: v.minRiskReward,
+	}
+	signal.Validation = validation
+
+	direction := strings.ToUpper(signal.Signal)
+	if direction != "BUY" && direction != "SELL" {
+		validation.Status = ValidationNotApplicable
+		return validation
+	}
+
+	risk, reward, ratio, err := calculateRiskReward(signal)
+	if err != nil {
+		validation.Issues = append(validation.Issues, err.Error())
+	} else {
+		validation.Risk = risk
+		validation.Reward = reward
+		validation.RiskRewardRatio = ratio
+		if ratio < v.minRiskReward {
+			validation.Issues = append(validation.Issues,
+				fmt.Sprintf("risk-reward 1:%.2f is below the required 1:%.2f", ratio, v.minRiskReward))
+		}
+	}
+
+	if len(validation.Issues) > 0 {
+		validation.Status = ValidationDowngraded
+		validation.OriginalSignal = direction
+		signal.Signal = "WAIT"
+		signal.Reason = fmt.Sprintf("%s\n\n[Downgraded from %s to WAIT: %s]", signal.Reason, direction, strings.Join(validation.Issues, "; "))
+	}
+
+	return validation
+}
+
+// Reject marks a signal that breaks an exchange rule as invalid. BUY/SELL signals are turned
+// into WAIT with the reason appended, and the outcome is recorded on the signal and returned.
+func (v *SignalValidator) Reject(signal *models.TradingSignal, reason error) *models.SignalValidation {
+	direction := strings.ToUpper(signal.Signal)
+	validation := &models.SignalValidation{
+		Status:         ValidationRejected,
+		OriginalSignal: direction,
+		MinRiskReward:  v.minRiskReward,
+		Issues:         []string{reason.Error()},
+	}
+	signal.Validation = validation
+
+	if direction == "BUY" || direction == "SELL" {
+		signal.Signal = "WAIT"
+		signal.Reason = fmt.Sprintf("%s\n\n[Rejected %s signal: %s]", signal.Reason, direction, reason.Error())
+	}
+
+	return validation
+}
+
+// calculateRiskReward calculates the risk, reward and reward/risk ratio of a BUY or SELL signal
+// and returns an error when the prices are not ordered correctly
+func calculateRiskReward(signal *models.TradingSignal) (float64, float64, float64, error) {
+	var risk, reward float64
+
+	switch strings.ToUpper(signal.Signal) {
+	case "BUY":
+		if !(signal.StopLoss < signal.BuyPrice && signal.BuyPrice < signal.TargetPrice) {
+			return 0, 0, 0, fmt.Errorf("BUY prices must satisfy stop loss < buy price < target (got stop %.2f, buy %.2f, target %.2f)",
+				signal.StopLoss, signal.BuyPrice, signal.TargetPrice)
+		}
+		risk = signal.BuyPrice - signal.StopLoss
+		reward = signal.TargetPrice - signal.BuyPrice
+	case "SELL":
+		if !(signal.TargetPrice < signal.BuyPrice && signal.BuyPrice < signal.StopLoss) {
+			return 0, 0, 0, fmt.Errorf("SELL prices must satisfy target < sell price < stop loss (got target %.2f, sell %.2f, stop %.2f)",
+				signal.TargetPrice, signal.BuyPrice, signal.StopLoss)
+		}
+		risk = signal.StopLoss - signal.BuyPrice
+		reward = signal.BuyPrice - signal.TargetPrice
+	default:
+		return 0, 0, 0, fmt.Errorf("invalid signal type: %s", signal.Signal)
+	}
+
+	return risk, reward, reward / risk, nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Signal generation stages, named in timeout and cancellation errors
+const (
+	StageFetchCandles  = "fetch_candles"
+	StagePreviousClose = "previous_close"
+	StageStrategy      = "strategy"
+	StageValidation    = "validation"
+)
+
+// stageObserverKey is the context key of a stage observer
+type stageObserverKey struct{}
+
+// withStageObserver returns a context whose signal generation reports each stage to observe as it starts
+func withStageObserver(ctx context.Context, observe func(stage string)) context.Context {
+	return context.WithValue(ctx, stageObserverKey{}, observe)
+}
+
+// reportStage tells the context's stage observer, if any, that a stage started
+func reportStage(ctx context.Context, stage string) {
+	if observe, ok := ctx.Value(stageObserverKey{}).(func(stage string)); ok {
+		observe(stage)
+	}
+}
+
+// StageTimeoutError is returned when a stage of signal generation exceeds its timeout
+type StageTimeoutError struct {
+	Stage   string
+	Timeout time.Duration
+}
+
+// Error names the stage that timed out
+func (e *StageTimeoutError) Error() string {
+	return fmt.Sprintf("timed out at stage %s after %s", e.Stage, e.Timeout)
+}
+
+// Unwrap lets callers match the timeout with errors.Is(err, context.DeadlineExceeded)
+func (e *StageTimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// runStage runs fn with a context bounded by the stage's timeout; a non-positive timeout
+// leaves it unbounded. Exceeding the timeout returns a *StageTimeoutError, and cancellation
+// of the parent context is reported with the stage it interrupted.
+func runStage(ctx context.Context, stage string, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("cancelled before stage %s: %w", stage, err)
+	}
+	reportStage(ctx, stage)
+
+	stageCtx, cancel := ctx, context.CancelFunc(func() {})
+	if timeout > 0 {
+		stageCtx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	err := fn(stageCtx)
+	switch {
+	case err == nil:
+		return nil
+	case ctx.Err() != nil:
+		return fmt.Errorf("cancelled at stage %s: %w", stage, ctx.Err())
+	case errors.Is(stageCtx.Err(), context.DeadlineExceeded):
+		return &StageTimeoutError{Stage: stage, Timeout: timeout}
+	default:
+		return err
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunStage(t *testing.T) {
+	blocking := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	t.Run("returns the result of the stage", func(t *testing.T) {
+		failure := errors.New("bad gateway")
+		if err := runStage(context.Background(), StageStrategy, time.Second, func(ctx context.Context) error { return nil }); err != nil {
+			t.Errorf("runStage returned error: %v", err)
+		}
+		if err := runStage(context.Background(), StageStrategy, 0, func(ctx context.Context) error { return failure }); err != failure {
+			t.Errorf("err = %v, want %v", err, failure)
+		}
+	})
+
+	t.Run("reports the stage that timed out", func(t *testing.T) {
+		err := runStage(context.Background(), StageFetchCandles, 10*time.Millisecond, blocking)
+
+		var timeout *StageTimeoutError
+		if !errors.As(err, &timeout) || timeout.Stage != StageFetchCandles {
+			t.Fatalf("err = %v, want a timeout at %s", err, StageFetchCandles)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timed out at stage fetch_candles") {
+			t.Errorf("err = %q does not name the stage or match context.DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("reports cancellation of the parent", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		err := runStage(ctx, StageStrategy, time.Minute, blocking)
+		var timeout *StageTimeoutError
+		if !errors.Is(err, context.Canceled) || errors.As(err, &timeout) {
+			t.Errorf("err = %v, want a cancellation", err)
+		}
+
+		called := false
+		err = runStage(ctx, StageStrategy, time.Minute, func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+		if called || !errors.Is(err, context.Canceled) {
+			t.Errorf("runStage ran a stage after cancellation: %v", err)
+		}
+	})
+}
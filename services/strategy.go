@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// DefaultStrategy is the strategy used when none is configured
+const DefaultStrategy = "gemini"
+
+// Strategy produces a trading signal from the analysis inputs of one symbol. Implementations
+// annotate the signal with the inputs; exchange rules and validation are applied afterwards.
+type Strategy interface {
+	// Name returns the name used to select the strategy
+	Name() string
+	// GenerateTradingSignal generates a signal for the inputs
+	GenerateTradingSignal(ctx context.Context, input *models.SignalInput) (*models.TradingSignal, error)
+}
+
+// StrategyRegistry holds the registered strategies and selects one per symbol
+type StrategyRegistry struct {
+	strategies       map[string]Strategy
+	defaultStrategy  string
+	symbolStrategies map[string]string
+	mutex            sync.RWMutex
+}
+
+// NewStrategyRegistry creates a new strategy registry
+func NewStrategyRegistry(defaultStrategy string, symbolStrategies map[string]string) *StrategyRegistry {
+	overrides := make(map[string]string, len(symbolStrategies))
+	for symbol, strategy := range symbolStrategies {
+		overrides[strings.ToUpper(symbol)] = strings.ToLower(strategy)
+	}
+
+	defaultStrategy = strings.ToLower(strings.TrimSpace(defaultStrategy))
+	if defaultStrategy == "" {
+		defaultStrategy = DefaultStrategy
+	}
+
+	return &StrategyRegistry{
+		strategies:       make(map[string]Strategy),
+		defaultStrategy:  defaultStrategy,
+		symbolStrategies: overrides,
+	}
+}
+
+// Register adds a strategy to the registry, replacing any strategy with the same name
+func (r *StrategyRegistry) Register(strategy Strategy) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.strategies[strings.ToLower(strategy.Name())] = strategy
+}
+
+// Get returns the strategy registered under a name
+func (r *StrategyRegistry) Get(name string) (Strategy, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	strategy, exists := r.strategies[strings.ToLower(strings.TrimSpace(name))]
+	if !exists {
+		return nil, fmt.Errorf("strategy %q is not registered (available: %s)", name, strings.Join(r.names(), ", "))
+	}
+	return strategy, nil
+}
+
+// StrategyFor returns the strategy configured for a symbol, falling back to the default strategy
+func (r *StrategyRegistry) StrategyFor(symbol models.Symbol) (Strategy, error) {
+	r.mutex.RLock()
+	name := symbolSetting(r.symbolStrategies, symbol)
+	r.mutex.RUnlock()
+
+	if name == "" {
+		name = r.defaultStrategy
+	}
+	return r.Get(name)
+}
+
+// Validate checks that the default strategy and every per-symbol override refer to registered strategies
+func (r *StrategyRegistry) Validate() error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if _, exists := r.strategies[r.defaultStrategy]; !exists {
+		return fmt.Errorf("default strategy %q is not registered (available: %s)", r.defaultStrategy, strings.Join(r.names(), ", "))
+	}
+	for symbol, name := range r.symbolStrategies {
+		if _, exists := r.strategies[name]; !exists {
+			return fmt.Errorf("strategy %q configured for %s is not registered (available: %s)", name, symbol, strings.Join(r.names(), ", "))
+		}
+	}
+	return nil
+}
+
+// StrategyNames returns the names of all registered strategies, sorted
+func (r *StrategyRegistry) StrategyNames() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.names()
+}
+
+// names returns the sorted strategy names; the caller holds the lock
+func (r *StrategyRegistry) names() []string {
+	names := make([]string, 0, len(r.strategies))
+	for name := range r.strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+var (
+	// ErrInvalidSymbol is returned when a symbol cannot be parsed
+	ErrInvalidSymbol = errors.New("invalid symbol")
+	// ErrUnknownSymbol is returned when a symbol is not in the known-symbol list
+	ErrUnknownSymbol = errors.New("unknown symbol")
+)
+
+// DefaultExchange is the exchange assumed for bare codes such as "BBCA"
+const DefaultExchange = "IDX"
+
+// exchanges lists the supported exchanges keyed by exchange code
+var exchanges = map[string]models.Exchange{
+	"IDX":   {Code: "IDX", Name: "Bursa Efek Indonesia", Suffix: ".JK", Currency: "Rp", Timezone: "Asia/Jakarta", OpenTime: "09:00", CloseTime: "16:00"},
+	"SGX":   {Code: "SGX", Name: "Singapore Exchange", Suffix: ".SI", Currency: "S$", Timezone: "Asia/Singapore", OpenTime: "09:00", CloseTime: "17:00"},
+	"BURSA": {Code: "BURSA", Name: "Bursa Malaysia", Suffix: ".KL", Currency: "RM", Timezone: "Asia/Kuala_Lumpur", OpenTime: "09:00", CloseTime: "17:00"},
+	"HKEX":  {Code: "HKEX", Name: "Hong Kong Stock Exchange", Suffix: ".HK", Currency: "HK$", Timezone: "Asia/Hong_Kong", OpenTime: "09:30", CloseTime: "16:00"},
+	"US":    {Code: "US", Name: "US Stock Market", Suffix: "", Currency: "$", Timezone: "America/New_York", OpenTime: "09:30", CloseTime: "16:00"},
+}
+
+// exchangeAliases maps accepted symbol suffixes to exchange codes
+var exchangeAliases = map[string]string{
+	"JK":     "IDX",
+	"IDX":    "IDX",
+	"SI":     "SGX",
+	"SGX":    "SGX",
+	"KL":     "BURSA",
+	"HK":     "HKEX",
+	"US":     "US",
+	"NYSE":   "US",
+	"NASDAQ": "US",
+}
+
+var symbolCodePattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9-]{0,9}$`)
+
+// LookupExchange returns the exchange for an exchange code
+func LookupExchange(code string) (models.Exchange, bool) {
+	exchange, exists := exchanges[strings.ToUpper(code)]
+	return exchange, exists
+}
+
+// ParseSymbol normalizes a symbol without checking it against the known-symbol list.
+// It accepts bare IDX codes ("BBCA"), suffixed codes ("BBCA.JK") and other exchange
+// suffixes ("D05.SI", "AAPL.US").
+func ParseSymbol(input string) (models.Symbol, error) {
+	value := strings.ToUpper(strings.TrimSpace(input))
+	if value == "" {
+		return models.Symbol{}, fmt.Errorf("%w: symbol is empty", ErrInvalidSymbol)
+	}
+
+	code, exchangeCode := value, DefaultExchange
+	if idx := strings.LastIndex(value, "."); idx != -1 {
+		alias, exists := exchangeAliases[value[idx+1:]]
+		if !exists {
+			return models.Symbol{}, fmt.Errorf("%w: unsupported exchange suffix in %s", ErrInvalidSymbol, input)
+		}
+		code, exchangeCode = value[:idx], alias
+	}
+
+	if !symbolCodePattern.MatchString(code) {
+		return models.Symbol{}, fmt.Errorf("%w: %s", ErrInvalidSymbol, input)
+	}
+
+	exchange := exchanges[exchangeCode]
+	return models.Symbol{
+		Code:     code,
+		Exchange: exchange.Code,
+		Ticker:   code + exchange.Suffix,
+	}, nil
+}
+
+// SymbolResolver normalizes user supplied symbols and validates them against a known-symbol list
+type SymbolResolver struct {
+	known        map[string]bool
+	allowUnknown bool
+}
+
+// NewSymbolResolver creates a new symbol resolver from a list of known symbols
+func NewSymbolResolver(knownSymbols []string, allowUnknown bool) *SymbolResolver {
+	known := make(map[string]bool, len(knownSymbols))
+	for _, input := range knownSymbols {
+		symbol, err := ParseSymbol(input)
+		if err != nil {
+			log.Printf("Ignoring invalid known symbol %q: %v", input, err)
+			continue
+		}
+		known[symbol.Ticker] = true
+	}
+
+	return &SymbolResolver{
+		known:        known,
+		allowUnknown: allowUnknown,
+	}
+}
+
+// Normalize parses a symbol and checks it against the known-symbol list
+func (r *SymbolResolver) Normalize(input string) (models.Symbol, error) {
+	symbol, err := ParseSymbol(input)
+	if err != nil {
+		return models.Symbol{}, err
+	}
+
+	if !r.allowUnknown && !r.known[symbol.Ticker] {
+		return models.Symbol{}, fmt.Errorf("%w: %s is not in the known-symbol list", ErrUnknownSymbol, symbol.Ticker)
+	}
+
+	return symbol, nil
+}
+
+// signalSymbol rebuilds the normalized symbol of a generated signal
+func signalSymbol(signal *models.TradingSignal) models.Symbol {
+	exchange, _ := LookupExchange(signal.Exchange)
+	return models.Symbol{
+		Code:     signal.StockSymbol,
+		Exchange: signal.Exchange,
+		Ticker:   signal.StockSymbol + exchange.Suffix,
+	}
+}
+
+// IsMarketOpen reports whether the exchange's regular session is open at the given time
+func IsMarketOpen(exchange models.Exchange, at time.Time) bool {
+	location, err := time.LoadLocation(exchange.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	local := at.In(location)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return false
+	}
+
+	current := local.Format("15:04")
+	return current >= exchange.OpenTime && current < exchange.CloseTime
+}
+
+// symbolSetting looks up a per-symbol setting by ticker first and then by bare code
+func symbolSetting(settings map[string]string, symbol models.Symbol) string {
+	if value, exists := settings[symbol.Ticker]; exists {
+		return value
+	}
+	return settings[symbol.Code]
+}
+
+// exchangeLocation returns the time zone of a symbol's exchange, falling back to UTC
+func exchangeLocation(symbol models.Symbol) *time.Location {
+	exchange, exists := LookupExchange(symbol.Exchange)
+	if !exists {
+		return time.UTC
+	}
+
+	location, err := time.LoadLocation(exchange.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
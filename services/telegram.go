@@ -1,33 +1,37 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
-	"log"
-
 	"github.com/farisdewantoro/golang-day-trading-signal/models"
 )
 
 // TelegramService handles sending messages via Telegram bot
 type TelegramService struct {
-	botToken string
-	chatID   string
-	client   *http.Client
+	botToken   string
+	chatID     string
+	client     *http.Client
+	dependency *Dependency
 }
 
-// NewTelegramService creates a new Telegram service
-func NewTelegramService(botToken, chatID string) *TelegramService {
+// NewTelegramService creates a new Telegram service whose API calls are guarded by dependency
+func NewTelegramService(botToken, chatID string, dependency *Dependency) *TelegramService {
 	return &TelegramService{
 		botToken: botToken,
 		chatID:   chatID,
 		client: &http.Client{
 			Timeout: 60 * time.Second,
 		},
+		dependency: dependency,
 	}
 }
 
@@ -43,13 +47,19 @@ func (t *TelegramService) SendSignalSummary(summary *models.SignalSummary) error
 	return t.sendMessage(message)
 }
 
+// SendSignalSummaryToChat sends a summary of all analyzed signals to a specific chat ID
+func (t *TelegramService) SendSignalSummaryToChat(chatID string, summary *models.SignalSummary) error {
+	message := t.formatSummaryMessage(summary)
+	return t.sendMessageToChat(chatID, message)
+}
+
 // SendRequestReceivedMessage sends a message indicating that a bulk analysis request has been received
-func (t *TelegramService) SendRequestReceivedMessage(totalStocks int) error {
+func (t *TelegramService) SendRequestReceivedMessage(totalStocks, workers int) error {
 	message := fmt.Sprintf(`📋 <b>BULK ANALYSIS REQUEST RECEIVED</b> 📋
 
 📊 <b>Analysis Details:</b>
    📈 Total Stocks: %d
-   ⏱️ Estimated Time: %d minutes
+   ⚡ Parallel Workers: %d
    🔄 Status: Processing...
 
 ⏰ <b>Request Time:</b> %s
@@ -58,60 +68,48 @@ Please wait while we analyze all stocks. You will receive a summary once the ana
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━`,
 		totalStocks,
-		(totalStocks*3)/60+1, // 3 seconds per stock + 1 minute buffer
+		workers,
 		time.Now().Format("2006-01-02 15:04:05"))
 
 	return t.sendMessage(message)
 }
 
+// SendBulkCancelledMessage sends a message to a chat indicating that a bulk analysis was cancelled
+func (t *TelegramService) SendBulkCancelledMessage(chatID string, analyzed, totalStocks int) error {
+	message := fmt.Sprintf(`🛑 <b>BULK ANALYSIS CANCELLED</b> 🛑
+
+📊 <b>Analysis Details:</b>
+   📈 Analyzed: %d of %d stocks
+   🔄 Status: Cancelled
+
+⏰ <b>Cancelled At:</b> %s
+
+No summary will be sent for this request.
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━`,
+		analyzed,
+		totalStocks,
+		time.Now().Format("2006-01-02 15:04:05"))
+
+	return t.sendMessageToChat(chatID, message)
+}
+
 // SetupWebhook sets up the Telegram webhook URL
 func (t *TelegramService) SetupWebhook(webhookURL string) error {
-	baseURL := "https://api.telegram.org/bot" + t.botToken + "/setWebhook"
-
 	params := url.Values{}
 	params.Add("url", webhookURL)
 
-	req, err := http.NewRequest("POST", baseURL, strings.NewReader(params.Encode()))
-	if err != nil {
-		return fmt.Errorf("failed to create webhook setup request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-
-	resp, err := t.client.Do(req)
-	if err != nil {
+	if err := t.callAPI("setWebhook", params); err != nil {
 		return fmt.Errorf("failed to setup webhook: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("Telegram API returned status: %d, body: %s", resp.StatusCode, string(body))
-	}
-
 	return nil
 }
 
 // DeleteWebhook removes the current webhook
 func (t *TelegramService) DeleteWebhook() error {
-	baseURL := "https://api.telegram.org/bot" + t.botToken + "/deleteWebhook"
-
-	req, err := http.NewRequest("POST", baseURL, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create webhook delete request: %w", err)
-	}
-
-	resp, err := t.client.Do(req)
-	if err != nil {
+	if err := t.callAPI("deleteWebhook", url.Values{}); err != nil {
 		return fmt.Errorf("failed to delete webhook: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("Telegram API returned status: %d, body: %s", resp.StatusCode, string(body))
-	}
-
 	return nil
 }
 
@@ -124,12 +122,18 @@ func (t *TelegramService) SendWelcomeMessage(chatID string) error {
 🔍 <b>Single Stock Analysis:</b>
    Send a stock symbol (e.g., BBCA, BBRI, ANTM)
    Example: <code>ANTM</code>
+   Optional interval and range: <code>ANTM 15m 5d</code>
 
 📊 <b>Bulk Analysis:</b>
    /bulk - Analyze all configured stocks
    /summary - Get summary of all stocks
+   /cancel - Cancel this chat's bulk analyses
    /stocks - Show all configured stocks
 
+📈 <b>Track Record:</b>
+   /stats - Hit rate of past signals
+   Example: <code>/stats BBCA</code>
+
 ❓ <b>Help:</b>
    /help - Show this help message
    /start - Start the bot
@@ -146,6 +150,7 @@ func (t *TelegramService) SendHelpMessage(chatID string) error {
 
 🔍 <b>How to use:</b>
    1. Send a stock symbol to get trading signal
+      (optionally followed by interval and range, e.g. <code>BBCA 15m 5d</code>)
    2. Wait for analysis to complete
    3. Receive detailed signal with buy/sell recommendations
 
@@ -153,9 +158,14 @@ func (t *TelegramService) SendHelpMessage(chatID string) error {
    /stocks - Show all configured stocks
    /bulk - Analyze all configured stocks (individual signals)
    /summary - Analyze all configured stocks (summary only)
+   /cancel - Cancel this chat's bulk analyses
+   /stats [SYMBOL] - Hit rate of past BUY/SELL signals
    /help - Show this help message
    /start - Start the bot
 
+🕯️ <b>Intervals:</b> 1m, 5m, 15m, 1h, 1d
+📅 <b>Ranges:</b> e.g. 1d, 2d, 5d, 1mo
+
 📊 <b>Signal Types:</b>
    🟢 BUY - Good opportunity to buy
    🔴 SELL - Consider selling
@@ -179,62 +189,65 @@ func (t *TelegramService) SendHelpMessage(chatID string) error {
 
 // sendMessage sends a text message to Telegram
 func (t *TelegramService) sendMessage(message string) error {
-	baseURL := "https://api.telegram.org/bot" + t.botToken + "/sendMessage"
-
-	params := url.Values{}
-	params.Add("chat_id", t.chatID)
-	params.Add("text", message)
-	params.Add("parse_mode", "HTML")
-
-	req, err := http.NewRequest("POST", baseURL, strings.NewReader(params.Encode()))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-
-	resp, err := t.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send Telegram message: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("Telegram API returned status: %d, body: %s", resp.StatusCode, string(body))
-	}
-
-	return nil
+	return t.sendMessageToChat(t.chatID, message)
 }
 
 // sendMessageToChat sends a message to a specific chat ID
 func (t *TelegramService) sendMessageToChat(chatID, message string) error {
-	baseURL := "https://api.telegram.org/bot" + t.botToken + "/sendMessage"
-
 	params := url.Values{}
 	params.Add("chat_id", chatID)
 	params.Add("text", message)
 	params.Add("parse_mode", "HTML")
 
-	req, err := http.NewRequest("POST", baseURL, strings.NewReader(params.Encode()))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	if err := t.callAPI("sendMessage", params); err != nil {
+		return fmt.Errorf("failed to send Telegram message: %w", err)
 	}
+	return nil
+}
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+// telegramErrorResponse is the part of a Telegram API error response that is used
+type telegramErrorResponse struct {
+	Description string `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"` // Seconds to wait when rate limited
+	} `json:"parameters"`
+}
 
-	resp, err := t.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send Telegram message: %w", err)
-	}
-	defer resp.Body.Close()
+// callAPI posts a form to a Telegram Bot API method, retrying rate limits and server errors
+func (t *TelegramService) callAPI(method string, params url.Values) error {
+	return t.dependency.Call(context.Background(), func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "POST", "https://api.telegram.org/bot"+t.botToken+"/"+method, strings.NewReader(params.Encode()))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		resp, err := t.client.Do(req)
+		if err != nil {
+			// The request URL contains the bot token, so keep it out of the error
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				err = urlErr.Err
+			}
+			return transportError(ctx, fmt.Errorf("Telegram %s request failed: %w", method, err))
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusOK {
+			return nil
+		}
 
-	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("Telegram API returned status: %d, body: %s", resp.StatusCode, string(body))
-	}
+		err = statusError(resp, fmt.Errorf("Telegram API returned status: %d, body: %s", resp.StatusCode, string(body)))
 
-	return nil
+		// Telegram reports how long to back off in the body rather than in Retry-After
+		var apiErr telegramErrorResponse
+		var retryable *RetryableError
+		if errors.As(err, &retryable) && json.Unmarshal(body, &apiErr) == nil && apiErr.Parameters.RetryAfter > 0 {
+			retryable.RetryAfter = time.Duration(apiErr.Parameters.RetryAfter) * time.Second
+		}
+		return err
+	})
 }
 
 // SendMessageToChat sends a message to a specific chat ID (public method)
@@ -248,37 +261,32 @@ func (t *TelegramService) SendTradingSignalToChat(chatID string, signal *models.
 	return t.sendMessageToChat(chatID, message)
 }
 
+// Name returns the notifier name
+func (t *TelegramService) Name() string {
+	return "telegram"
+}
+
+// NotifySignal sends a trading signal to a chat
+func (t *TelegramService) NotifySignal(chatID string, signal *models.TradingSignal) error {
+	return t.SendTradingSignalToChat(chatID, signal)
+}
+
+// NotifySummary sends a bulk analysis summary to a chat
+func (t *TelegramService) NotifySummary(chatID string, summary *models.SignalSummary) error {
+	return t.SendSignalSummaryToChat(chatID, summary)
+}
+
 // calculateRiskRewardRatio calculates the risk-reward ratio for a trading signal
 func (t *TelegramService) calculateRiskRewardRatio(signal *models.TradingSignal) (float64, float64, float64, error) {
-	if signal.Signal == "WAIT" {
-		return 0, 0, 0, nil
-	}
-
-	var risk, reward float64
-
-	if signal.Signal == "BUY" {
-		risk = signal.BuyPrice - signal.StopLoss
-		reward = signal.TargetPrice - signal.BuyPrice
-	} else if signal.Signal == "SELL" {
-		risk = signal.StopLoss - signal.BuyPrice
-		reward = signal.BuyPrice - signal.TargetPrice
-	} else {
-		log.Println(fmt.Errorf("invalid signal type: %s", signal.Signal))
-		return 0, 0, 0, nil
-	}
+	return calculateRiskReward(signal)
+}
 
-	// Check if risk and reward are positive
-	if risk <= 0 {
-		log.Println(fmt.Errorf("invalid risk calculation: risk must be positive, got %.2f", risk))
-		return 0, 0, 0, nil
+// currencyFor returns the currency symbol used to format prices for an exchange
+func currencyFor(exchangeCode string) string {
+	if exchange, exists := LookupExchange(exchangeCode); exists {
+		return exchange.Currency
 	}
-	if reward <= 0 {
-		log.Println(fmt.Errorf("invalid reward calculation: reward must be positive, got %.2f", reward))
-		return 0, 0, 0, nil
-	}
-
-	ratio := reward / risk
-	return risk, reward, ratio, nil
+	return "$"
 }
 
 // formatSignalMessage formats the trading signal for Telegram
@@ -295,6 +303,8 @@ func (t *TelegramService) formatSignalMessage(signal *models.TradingSignal) stri
 		emoji = "⚪"
 	}
 
+	currency := currencyFor(signal.Exchange)
+
 	// Create title with emoji and signal type
 	title := fmt.Sprintf("%s <b>TRADING SIGNAL: %s %s</b> %s",
 		emoji,
@@ -304,13 +314,13 @@ func (t *TelegramService) formatSignalMessage(signal *models.TradingSignal) stri
 
 	message := fmt.Sprintf(`%s
 
-💰 <b>Buy Price:</b> $%.2f
-🎯 <b>Target Price:</b> $%.2f
-🛑 <b>Stop Loss:</b> $%.2f`,
+💰 <b>Buy Price:</b> %s%.2f
+🎯 <b>Target Price:</b> %s%.2f
+🛑 <b>Stop Loss:</b> %s%.2f`,
 		title,
-		signal.BuyPrice,
-		signal.TargetPrice,
-		signal.StopLoss)
+		currency, signal.BuyPrice,
+		currency, signal.TargetPrice,
+		currency, signal.StopLoss)
 
 	// Add risk-reward ratio if not WAIT signal
 	if signal.Signal != "WAIT" {
@@ -319,13 +329,110 @@ func (t *TelegramService) formatSignalMessage(signal *models.TradingSignal) stri
 			message += fmt.Sprintf(`
 
 ⚖️ <b>Risk-Reward Analysis:</b>
-   💸 Risk: $%.2f
-   💰 Reward: $%.2f
+   💸 Risk: %s%.2f
+   💰 Reward: %s%.2f
    📊 Ratio: 1:%.2f`,
-				risk, reward, ratio)
+				currency, risk, currency, reward, ratio)
+		}
+	}
+
+	if exchange, exists := LookupExchange(signal.Exchange); exists {
+		marketStatus := "closed"
+		if IsMarketOpen(exchange, time.Now()) {
+			marketStatus = "open"
+		}
+		message += fmt.Sprintf(`
+
+🏛️ <b>Exchange:</b> %s (market %s)`,
+			exchange.Name, marketStatus)
+	}
+
+	if signal.Interval != "" {
+		message += fmt.Sprintf(`
+
+🕯️ <b>Timeframe:</b> %s candles, %s range`,
+			signal.Interval, signal.Range)
+	}
+
+	if len(signal.Patterns) > 0 {
+		message += "\n\n🕯️ <b>Candlestick Patterns:</b>"
+		for _, pattern := range signal.Patterns {
+			message += fmt.Sprintf("\n   • %s (%s, strength %d%%) at %s",
+				strings.ReplaceAll(pattern.Name, "_", " "),
+				pattern.Direction,
+				pattern.Strength,
+				pattern.Timestamp.Format("15:04"))
+		}
+	}
+
+	if signal.Levels != nil && (signal.Levels.NearestSupport > 0 || signal.Levels.NearestResistance > 0) {
+		message += fmt.Sprintf(`
+
+📐 <b>Key Levels:</b>
+   🔻 Nearest Support: %s%.2f
+   🔺 Nearest Resistance: %s%.2f`,
+			currency, signal.Levels.NearestSupport,
+			currency, signal.Levels.NearestResistance)
+		if signal.Levels.Classic != nil {
+			message += fmt.Sprintf("\n   ⚖️ Pivot: %s%.2f", currency, signal.Levels.Classic.Pivot)
 		}
 	}
 
+	if signal.Validation != nil && (signal.Validation.Status == ValidationDowngraded || signal.Validation.Status == ValidationRejected) {
+		action := "Downgraded from"
+		if signal.Validation.Status == ValidationRejected {
+			action = "Rejected"
+		}
+		message += fmt.Sprintf(`
+
+🚫 <b>%s %s:</b>`, action, signal.Validation.OriginalSignal)
+		for _, issue := range signal.Validation.Issues {
+			message += fmt.Sprintf("\n   • %s", html.EscapeString(issue))
+		}
+	}
+
+	if signal.PriceLimits != nil {
+		message += fmt.Sprintf(`
+
+🚧 <b>Price Limits:</b> ARB %s%.0f - ARA %s%.0f (prev close %s%.0f)`,
+			currency, signal.PriceLimits.LowerLimit,
+			currency, signal.PriceLimits.UpperLimit,
+			currency, signal.PriceLimits.PreviousClose)
+	}
+
+	if signal.PriceAdjusted && signal.OriginalPrices != nil {
+		message += fmt.Sprintf(`
+
+🔧 <b>Prices Adjusted:</b> model suggested buy %s%.2f, target %s%.2f, stop %s%.2f`,
+			currency, signal.OriginalPrices.BuyPrice,
+			currency, signal.OriginalPrices.TargetPrice,
+			currency, signal.OriginalPrices.StopLoss)
+		for _, adjustment := range signal.Adjustments {
+			message += fmt.Sprintf("\n   • %s", html.EscapeString(adjustment))
+		}
+	}
+
+	if len(signal.LevelWarnings) > 0 {
+		message += "\n\n⚠️ <b>Level Check:</b>"
+		for _, warning := range signal.LevelWarnings {
+			message += fmt.Sprintf("\n   • %s", html.EscapeString(warning))
+		}
+	}
+
+	if signal.Ensemble != nil {
+		votes := 0
+		for _, vote := range signal.Ensemble.Votes {
+			if vote.Error == "" {
+				votes++
+			}
+		}
+		message += fmt.Sprintf(`
+
+🤝 <b>Ensemble Agreement:</b> %.0f%% for %s across %d votes (%s, needs %.0f%%)`,
+			signal.Ensemble.Agreement, signal.Ensemble.Consensus, votes,
+			signal.Ensemble.Method, signal.Ensemble.MinAgreement)
+	}
+
 	message += fmt.Sprintf(`
 
 📈 <b>Confidence Level:</b> %d%%
@@ -333,27 +440,27 @@ func (t *TelegramService) formatSignalMessage(signal *models.TradingSignal) stri
 📝 <b>Signal Reason:</b>
 %s`,
 		signal.Confidence,
-		signal.Reason)
+		html.EscapeString(signal.Reason))
 
 	// Add OHLCV analysis if available
 	if signal.OHLCVAnalysis != nil {
 		message += fmt.Sprintf(`
 
 📊 <b>Current OHLCV Data:</b>
-   📈 Open: $%.2f
-   🔺 High: $%.2f
-   🔻 Low: $%.2f
-   📉 Close: $%.2f
+   📈 Open: %s%.2f
+   🔺 High: %s%.2f
+   🔻 Low: %s%.2f
+   📉 Close: %s%.2f
    📊 Volume: %d
 
 📋 <b>Technical Analysis:</b>
 %s`,
-			signal.OHLCVAnalysis.Open,
-			signal.OHLCVAnalysis.High,
-			signal.OHLCVAnalysis.Low,
-			signal.OHLCVAnalysis.Close,
+			currency, signal.OHLCVAnalysis.Open,
+			currency, signal.OHLCVAnalysis.High,
+			currency, signal.OHLCVAnalysis.Low,
+			currency, signal.OHLCVAnalysis.Close,
 			signal.OHLCVAnalysis.Volume,
-			signal.OHLCVAnalysis.Explanation)
+			html.EscapeString(signal.OHLCVAnalysis.Explanation))
 	}
 
 	message += fmt.Sprintf(`
@@ -394,13 +501,14 @@ func (t *TelegramService) formatSummaryMessage(summary *models.SignalSummary) st
 	if len(summary.BuySignals) > 0 {
 		message += "\n\n🟢 <b>BUY SIGNALS:</b>"
 		for _, signal := range summary.BuySignals {
+			currency := currencyFor(signal.Exchange)
 			_, _, ratio, err := t.calculateRiskRewardRatio(signal)
 			if err == nil {
-				message += fmt.Sprintf("\n   • %s - Confidence: %d%% - Buy: $%.2f - Target: $%.2f - Cut Loss: $%.2f - R:R 1:%.2f",
-					signal.StockSymbol, signal.Confidence, signal.BuyPrice, signal.TargetPrice, signal.StopLoss, ratio)
+				message += fmt.Sprintf("\n   • %s - Confidence: %d%% - Buy: %s%.2f - Target: %s%.2f - Cut Loss: %s%.2f - R:R 1:%.2f",
+					signal.StockSymbol, signal.Confidence, currency, signal.BuyPrice, currency, signal.TargetPrice, currency, signal.StopLoss, ratio)
 			} else {
-				message += fmt.Sprintf("\n   • %s - Confidence: %d%% - Buy: $%.2f - Target: $%.2f - Cut Loss: $%.2f",
-					signal.StockSymbol, signal.Confidence, signal.BuyPrice, signal.TargetPrice, signal.StopLoss)
+				message += fmt.Sprintf("\n   • %s - Confidence: %d%% - Buy: %s%.2f - Target: %s%.2f - Cut Loss: %s%.2f",
+					signal.StockSymbol, signal.Confidence, currency, signal.BuyPrice, currency, signal.TargetPrice, currency, signal.StopLoss)
 			}
 		}
 	}
@@ -409,13 +517,14 @@ func (t *TelegramService) formatSummaryMessage(summary *models.SignalSummary) st
 	if len(summary.SellSignals) > 0 {
 		message += "\n\n🔴 <b>SELL SIGNALS:</b>"
 		for _, signal := range summary.SellSignals {
+			currency := currencyFor(signal.Exchange)
 			_, _, ratio, err := t.calculateRiskRewardRatio(signal)
 			if err == nil {
-				message += fmt.Sprintf("\n   • %s - Confidence: %d%% - Stop Loss: $%.2f - R:R 1:%.2f",
-					signal.StockSymbol, signal.Confidence, signal.StopLoss, ratio)
+				message += fmt.Sprintf("\n   • %s - Confidence: %d%% - Stop Loss: %s%.2f - R:R 1:%.2f",
+					signal.StockSymbol, signal.Confidence, currency, signal.StopLoss, ratio)
 			} else {
-				message += fmt.Sprintf("\n   • %s - Confidence: %d%% - Stop Loss: $%.2f",
-					signal.StockSymbol, signal.Confidence, signal.StopLoss)
+				message += fmt.Sprintf("\n   • %s - Confidence: %d%% - Stop Loss: %s%.2f",
+					signal.StockSymbol, signal.Confidence, currency, signal.StopLoss)
 			}
 		}
 	}
@@ -485,3 +594,42 @@ func (t *TelegramService) SendStocksListMessage(chatID string, stockSymbols []st
 
 	return t.sendMessageToChat(chatID, message)
 }
+
+// SendOutcomeStatsMessage sends the tracked hit rates of past signals
+func (t *TelegramService) SendOutcomeStatsMessage(chatID, label string, stats *models.OutcomeStats) error {
+	message := fmt.Sprintf(`📈 <b>SIGNAL TRACK RECORD</b> 📈
+
+🏷️ <b>Scope:</b> %s
+📊 <b>Tracked Signals:</b> %d
+
+🎯 <b>Target Hit:</b> %d
+🛑 <b>Stop Hit:</b> %d
+⌛ <b>Expired:</b> %d
+🚫 <b>Not Filled:</b> %d
+⏳ <b>Open / Pending:</b> %d / %d
+
+✅ <b>Hit Rate:</b> %.1f%%
+📥 <b>Fill Rate:</b> %.1f%%
+💹 <b>Avg Return:</b> %.2f%%
+📈 <b>Avg Max Favorable:</b> %.2f%%
+📉 <b>Avg Max Adverse:</b> %.2f%%
+⏱️ <b>Avg Time to Outcome:</b> %.0f min
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━`,
+		html.EscapeString(label),
+		stats.Tracked,
+		stats.TargetHits,
+		stats.StopHits,
+		stats.Expired,
+		stats.NotFilled,
+		stats.Open,
+		stats.Pending,
+		stats.HitRate,
+		stats.FillRate,
+		stats.AvgReturnPct,
+		stats.AvgMaxFavorablePct,
+		stats.AvgMaxAdversePct,
+		stats.AvgTimeToOutcomeMins)
+
+	return t.sendMessageToChat(chatID, message)
+}
@@ -1,101 +1,583 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/farisdewantoro/golang-day-trading-signal/indicators"
+	"github.com/farisdewantoro/golang-day-trading-signal/levels"
 	"github.com/farisdewantoro/golang-day-trading-signal/models"
+	"github.com/farisdewantoro/golang-day-trading-signal/patterns"
 )
 
 // TradingSignalService orchestrates the entire trading signal generation process
 type TradingSignalService struct {
-	yahooService    *YahooFinanceService
-	geminiService   *GeminiAIService
+	marketData      *MarketDataRegistry
+	symbols         *SymbolResolver
+	validator       *SignalValidator
+	llmProviders    []LLMProvider
+	strategies      *StrategyRegistry
+	fallback        Strategy
 	telegramService *TelegramService
+	notifiers       []Notifier          // Chats' destinations of dispatched signals and summaries, Telegram first
+	broadcasters    []BroadcastNotifier // Destinations sent each signal and summary once, such as webhooks
+	alertPolicy     *AlertPolicy
+	store           SignalStore
 	config          *models.Config
-	signalCache     map[string]time.Time
-	cacheMutex      sync.RWMutex
+	dependencies    []*Dependency // External services guarded by retries and circuit breakers
+
+	// ctx is cancelled when the service closes; work that outlives a request runs under it
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	jobs *JobManager // Bulk analysis jobs
+	feed *SignalFeed // Live feed of generated signals
 }
 
 // NewTradingSignalService creates a new trading signal service
 func NewTradingSignalService(config *models.Config) (*TradingSignalService, error) {
-	geminiService, err := NewGeminiAIService(config.GeminiAPIKey)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Gemini service: %w", err)
+	strategies := NewStrategyRegistry(config.Strategy, config.SymbolStrategies)
+	for _, strategy := range RuleStrategies() {
+		strategies.Register(strategy)
+	}
+
+	yahoo := newDependency("yahoo", config).Limit(NewTokenBucket(config.YahooRequestsPerMin), nil)
+	telegram := newDependency("telegram", config)
+	dependencies := []*Dependency{yahoo, telegram}
+
+	// LLM providers are only available when configured; the rule strategies run without them
+	var llmProviders []LLMProvider
+	if config.GeminiAPIKey != "" {
+		dependency := newDependency("gemini", config).Limit(NewTokenBucket(config.GeminiRequestsPerMin), NewTokenBucket(config.GeminiTokensPerMin))
+		dependencies = append(dependencies, dependency)
+		gemini, err := NewGeminiProvider(config.GeminiAPIKey, config.GeminiLLM, dependency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Gemini service: %w", err)
+		}
+		llmProviders = append(llmProviders, gemini)
+	}
+	if config.OpenAIBaseURL != "" {
+		dependency := newDependency("openai", config)
+		dependencies = append(dependencies, dependency)
+		llmProviders = append(llmProviders, NewOpenAIProvider(config.OpenAIBaseURL, config.OpenAIAPIKey, config.OpenAILLM, config.OpenAIStructuredOutput, dependency))
+	}
+	for _, provider := range llmProviders {
+		strategies.Register(NewLLMStrategy(provider, config.LLMRepairAttempts))
+	}
+
+	if len(config.EnsembleStrategies) > 0 {
+		ensemble, err := NewEnsembleStrategy(strategies, config.EnsembleStrategies, config.EnsembleMethod, config.EnsembleMinAgreement)
+		if err != nil {
+			closeProviders(llmProviders)
+			return nil, fmt.Errorf("invalid ensemble configuration: %w", err)
+		}
+		strategies.Register(ensemble)
+	}
+
+	if err := strategies.Validate(); err != nil {
+		closeProviders(llmProviders)
+		return nil, fmt.Errorf("invalid strategy configuration: %w", err)
+	}
+	var fallback Strategy
+	if config.FallbackStrategy != "" {
+		var err error
+		if fallback, err = strategies.Get(config.FallbackStrategy); err != nil {
+			closeProviders(llmProviders)
+			return nil, fmt.Errorf("invalid FALLBACK_STRATEGY: %w", err)
+		}
+	}
+
+	marketData := NewMarketDataRegistry(config.MarketDataProvider, config.MarketDataSymbolProviders)
+	marketData.Register(NewYahooFinanceService(yahoo))
+	if config.MarketDataCSVDir != "" {
+		marketData.Register(NewCSVMarketDataProvider(config.MarketDataCSVDir))
+	}
+	if config.MarketDataHTTPURL != "" {
+		marketData.Register(NewHTTPMarketDataProvider(config.MarketDataHTTPURL))
+	}
+	if err := marketData.Validate(); err != nil {
+		closeProviders(llmProviders)
+		return nil, fmt.Errorf("invalid market data configuration (csv requires MARKET_DATA_CSV_DIR, http requires MARKET_DATA_HTTP_URL): %w", err)
+	}
+
+	config.PriceLimitMode = strings.ToLower(strings.TrimSpace(config.PriceLimitMode))
+	if config.PriceLimitMode != PriceLimitModeClamp && config.PriceLimitMode != PriceLimitModeReject {
+		closeProviders(llmProviders)
+		return nil, fmt.Errorf("invalid PRICE_LIMIT_MODE %q (expected %s or %s)", config.PriceLimitMode, PriceLimitModeClamp, PriceLimitModeReject)
+	}
+
+	telegramService := NewTelegramService(config.TelegramBotToken, config.TelegramChatID, telegram)
+	notifiers := []Notifier{telegramService}
+	var broadcasters []BroadcastNotifier
+	if len(config.NotifyWebhookURLs) > 0 {
+		var endpoints []WebhookEndpoint
+		for _, webhookURL := range config.NotifyWebhookURLs {
+			// Name the dependency by host so that the health check does not expose URL credentials
+			name := "webhook"
+			if parsed, err := url.Parse(webhookURL); err == nil && parsed.Host != "" {
+				name = "webhook " + parsed.Host
+			}
+			dependency := newDependency(name, config)
+			dependencies = append(dependencies, dependency)
+			endpoints = append(endpoints, WebhookEndpoint{URL: webhookURL, Dependency: dependency})
+		}
+		webhook, err := NewWebhookNotifier(endpoints, config.NotifyWebhookSecret,
+			time.Duration(config.NotifyWebhookTimeoutSecs)*time.Second, config.NotifyWebhookDeadLetterPath)
+		if err != nil {
+			closeProviders(llmProviders)
+			return nil, fmt.Errorf("invalid webhook notifier configuration: %w", err)
+		}
+		broadcasters = append(broadcasters, webhook)
+	}
+
+	knownSymbols := append([]string{config.DefaultStockSymbol}, config.StockSymbols...)
+	knownSymbols = append(knownSymbols, config.KnownSymbols...)
+
+	var store SignalStore
+	if config.SignalHistoryEnabled {
+		boltStore, err := NewBoltSignalStore(config.SignalStorePath)
+		if err != nil {
+			closeProviders(llmProviders)
+			return nil, err
+		}
+		store = boltStore
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
 	return &TradingSignalService{
-		yahooService:    NewYahooFinanceService(),
-		geminiService:   geminiService,
-		telegramService: NewTelegramService(config.TelegramBotToken, config.TelegramChatID),
+		marketData:      marketData,
+		symbols:         NewSymbolResolver(knownSymbols, config.AllowUnknownSymbols),
+		validator:       NewSignalValidator(config.MinRiskRewardRatio),
+		llmProviders:    llmProviders,
+		strategies:      strategies,
+		fallback:        fallback,
+		telegramService: telegramService,
+		notifiers:       notifiers,
+		broadcasters:    broadcasters,
+		alertPolicy:     NewAlertPolicy(config),
+		store:           store,
 		config:          config,
-		signalCache:     make(map[string]time.Time),
+		dependencies:    dependencies,
+		ctx:             ctx,
+		cancel:          cancel,
+		jobs:            NewJobManager(),
+		feed:            NewSignalFeed(),
 	}, nil
 }
 
-// GenerateSignal generates a trading signal for a given stock symbol
-func (t *TradingSignalService) GenerateSignal(symbol string) (*models.TradingSignal, error) {
+// GenerateSignal generates a trading signal for a given stock symbol.
+// Empty interval or range values fall back to the per-symbol and global configuration.
+// Cancelling ctx abandons the signal; each stage is also bounded by its configured timeout.
+func (t *TradingSignalService) GenerateSignal(ctx context.Context, rawSymbol, interval, dataRange string) (*models.TradingSignal, error) {
+
+	symbol, err := t.symbols.Normalize(rawSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	interval, dataRange, err = t.resolveCandleSettings(symbol, interval, dataRange)
+	if err != nil {
+		return nil, err
+	}
 
-	log.Printf("Generating trading signal for %s", symbol)
+	log.Printf("Generating trading signal for %s (%s candles, %s range)", symbol.Ticker, interval, dataRange)
 
 	// Fetch OHLC data
-	ohlcData, err := t.yahooService.FetchOHLCData(symbol)
+	var ohlcData []models.OHLCData
+	err = runStage(ctx, StageFetchCandles, t.marketDataTimeout(), func(ctx context.Context) error {
+		var err error
+		ohlcData, err = t.marketData.FetchCandles(ctx, symbol, interval, dataRange)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch OHLC data: %w", err)
 	}
 
 	if len(ohlcData) == 0 {
-		return nil, fmt.Errorf("no OHLC data available for %s", symbol)
+		return nil, fmt.Errorf("no OHLC data available for %s", symbol.Ticker)
 	}
 
-	log.Printf("Fetched %d OHLC data points for %s", len(ohlcData), symbol)
+	log.Printf("Fetched %d OHLC data points for %s", len(ohlcData), symbol.Ticker)
+
+	var previousClose float64
+	if symbol.Exchange == "IDX" {
+		previousClose = t.fetchPreviousClose(ctx, symbol, ohlcData, time.Now())
+	}
+	input := t.buildSignalInput(symbol, interval, dataRange, ohlcData, previousClose, time.Time{})
+
+	strategy, err := t.strategies.StrategyFor(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	signal, err := t.evaluateSignal(ctx, strategy, input)
+	if err != nil && ctx.Err() == nil && t.fallback != nil && t.fallback != strategy {
+		// e.g. the Gemini quota ran out or the call timed out; a rule strategy still produces a signal
+		log.Printf("%s strategy failed for %s, falling back to %s: %v", strategy.Name(), symbol.Ticker, t.fallback.Name(), err)
+		signal, err = t.evaluateSignal(ctx, t.fallback, input)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	t.recordSignal(signal, input)
+	t.feed.Publish(signal)
+
+	return signal, nil
+}
+
+// buildSignalInput computes the indicators, patterns and levels for a window of candles.
+// A positive previous close sets the IDX price limits; a zero asOf means the signal is for now.
+func (t *TradingSignalService) buildSignalInput(symbol models.Symbol, interval, dataRange string, ohlcData []models.OHLCData, previousClose float64, asOf time.Time) *models.SignalInput {
+	input := &models.SignalInput{
+		Symbol:        symbol,
+		Interval:      interval,
+		Range:         dataRange,
+		OHLCData:      ohlcData,
+		Indicators:    indicators.Compute(ohlcData),
+		Patterns:      patterns.DetectRecent(ohlcData, t.config.PatternLookback),
+		Levels:        levels.Compute(ohlcData, exchangeLocation(symbol)),
+		PreviousClose: previousClose,
+		MinRiskReward: t.config.MinRiskRewardRatio,
+		AsOf:          asOf,
+	}
+	if symbol.Exchange == "IDX" && previousClose > 0 {
+		input.PriceLimits = IDXPriceLimits(previousClose)
+	}
+	return input
+}
+
+// evaluateSignal runs a strategy on the inputs, then applies the exchange price rules, the
+// risk-reward validation and the level sanity check. The signal is neither stored nor sent.
+func (t *TradingSignalService) evaluateSignal(ctx context.Context, strategy Strategy, input *models.SignalInput) (*models.TradingSignal, error) {
+	symbol := input.Symbol
+
+	// Skip the strategy when the pre-filter is enabled and there is no pattern to act on
+	if t.config.PatternPrefilter && len(input.Patterns) == 0 {
+		log.Printf("No candlestick pattern in the last %d candles for %s, skipping AI analysis", t.config.PatternLookback, symbol.Ticker)
+		signal := &models.TradingSignal{
+			Signal: "WAIT",
+			Reason: fmt.Sprintf("No candlestick pattern detected in the last %d candles; AI analysis skipped", t.config.PatternLookback),
+		}
+		annotateSignal(signal, input)
+		t.validator.Validate(signal)
+		return signal, nil
+	}
 
-	// Generate AI signal
-	signal, err := t.geminiService.GenerateTradingSignal(symbol, ohlcData)
+	var signal *models.TradingSignal
+	err := runStage(ctx, StageStrategy, t.strategyTimeout(), func(ctx context.Context) error {
+		var err error
+		signal, err = strategy.GenerateTradingSignal(ctx, input)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate AI signal: %w", err)
+		return nil, fmt.Errorf("failed to generate %s signal: %w", strategy.Name(), err)
+	}
+
+	// Snap prices to the exchange's tick grid and the session's price limits
+	reportStage(ctx, StageValidation)
+	var limitErr error
+	if symbol.Exchange == "IDX" {
+		if err := applyIDXTickRules(signal); err != nil {
+			return nil, err
+		}
+		if input.PriceLimits != nil {
+			limitErr = applyIDXPriceLimits(signal, input.PriceLimits, t.config.PriceLimitMode)
+		}
+		if signal.PriceAdjusted {
+			log.Printf("Adjusted %s prices: %s", symbol.Ticker, strings.Join(signal.Adjustments, "; "))
+		}
 	}
 
-	// // Send to Telegram if confidence is high enough
-	// if err := t.telegramService.SendTradingSignal(signal); err != nil {
-	// 	log.Printf("Failed to send signal to Telegram: %v", err)
-	// 	// Don't return error here, as the signal was generated successfully
-	// } else {
-	// 	log.Printf("Signal sent to Telegram for %s", symbol)
-	// }
+	if limitErr != nil {
+		// Signals outside the session's limits are kept but marked invalid
+		validation := t.validator.Reject(signal, limitErr)
+		log.Printf("Rejected %s signal for %s: %s", validation.OriginalSignal, symbol.Ticker, strings.Join(validation.Issues, "; "))
+	} else if validation := t.validator.Validate(signal); validation.Status == ValidationDowngraded {
+		// Enforce price ordering and the minimum risk-reward ratio
+		log.Printf("Downgraded %s signal for %s to WAIT: %s", validation.OriginalSignal, symbol.Ticker, strings.Join(validation.Issues, "; "))
+	}
+
+	// Sanity-check the strategy's prices against the computed levels
+	signal.LevelWarnings = levels.Check(signal, input.Levels)
+	for _, warning := range signal.LevelWarnings {
+		log.Printf("Level check for %s: %s", symbol.Ticker, warning)
+	}
 
 	return signal, nil
 }
 
-// canGenerateSignal checks if enough time has passed since the last signal
-func (t *TradingSignalService) canGenerateSignal(symbol string) bool {
-	t.cacheMutex.RLock()
-	defer t.cacheMutex.RUnlock()
+// recordSignal stores a generated signal with a summary of its inputs in the signal history.
+// Storage failures are logged and do not fail signal generation.
+func (t *TradingSignalService) recordSignal(signal *models.TradingSignal, input *models.SignalInput) {
+	if t.store == nil {
+		return
+	}
+
+	first, last := input.OHLCData[0], input.OHLCData[len(input.OHLCData)-1]
+	inputs := &models.SignalRecordInputs{
+		Interval:      input.Interval,
+		Range:         input.Range,
+		CandleCount:   len(input.OHLCData),
+		FirstCandle:   first.Timestamp,
+		LastCandle:    last.Timestamp,
+		LastClose:     last.Close,
+		PreviousClose: input.PreviousClose,
+	}
+	if provider, err := t.marketData.ProviderFor(input.Symbol); err == nil {
+		inputs.Provider = provider.Name()
+	}
+
+	record := &models.SignalRecord{
+		Symbol:     input.Symbol.Code,
+		Ticker:     input.Symbol.Ticker,
+		SignalType: strings.ToUpper(signal.Signal),
+		Confidence: signal.Confidence,
+		Model:      signal.Model,
+		PromptHash: signal.PromptHash,
+		Inputs:     inputs,
+		Signal:     signal,
+	}
+	if signal.Validation != nil {
+		record.ValidationStatus = signal.Validation.Status
+	}
+
+	if err := t.store.SaveSignal(record); err != nil {
+		log.Printf("Failed to store signal for %s: %v", input.Symbol.Ticker, err)
+	}
+}
+
+// recordSummary stores a bulk analysis summary in the signal history
+func (t *TradingSignalService) recordSummary(mode string, summary *models.SignalSummary) {
+	if t.store == nil {
+		return
+	}
+
+	record := &models.SummaryRecord{
+		Mode:    mode,
+		Summary: summary,
+	}
+	if err := t.store.SaveSummary(record); err != nil {
+		log.Printf("Failed to store signal summary: %v", err)
+	}
+}
+
+// annotateSignal copies the symbol, candle settings and computed analysis from the input onto a signal
+func annotateSignal(signal *models.TradingSignal, input *models.SignalInput) {
+	signal.StockSymbol = input.Symbol.Code
+	signal.Exchange = input.Symbol.Exchange
+	signal.Interval = input.Interval
+	signal.Range = input.Range
+	signal.Indicators = input.Indicators
+	signal.Patterns = input.Patterns
+	signal.Levels = input.Levels
+	signal.PriceLimits = input.PriceLimits
+	signal.GeneratedAt = input.AsOf
+	if signal.GeneratedAt.IsZero() {
+		signal.GeneratedAt = time.Now()
+	}
+}
 
-	lastSignal, exists := t.signalCache[symbol]
-	if !exists {
-		return true
+// fetchPreviousClose returns the close that today's price limits are based on: the close of the
+// last session before the one being traded at the given time. Before the close that is the
+// session before today; after the close it is today's session, which the next session trades
+// against. It uses daily candles from the symbol's provider and falls back to the intraday
+// candles. Zero means the previous close is unknown.
+func (t *TradingSignalService) fetchPreviousClose(ctx context.Context, symbol models.Symbol, ohlcData []models.OHLCData, at time.Time) float64 {
+	location := exchangeLocation(symbol)
+	tradingSession := tradingSessionDate(symbol, at)
+
+	var daily []models.OHLCData
+	err := runStage(ctx, StagePreviousClose, t.marketDataTimeout(), func(ctx context.Context) error {
+		var err error
+		daily, err = t.marketData.FetchCandles(ctx, symbol, "1d", "5d")
+		return err
+	})
+	if err != nil {
+		log.Printf("Failed to fetch daily candles for %s: %v", symbol.Ticker, err)
+	}
+	if close := lastCloseBefore(daily, location, tradingSession); close > 0 {
+		return close
 	}
 
-	cooldownDuration := time.Duration(t.config.SignalCooldownMins) * time.Minute
-	return time.Since(lastSignal) >= cooldownDuration
+	return lastCloseBefore(ohlcData, location, tradingSession)
+}
+
+// tradingSessionDate returns the date (YYYY-MM-DD, exchange time) of the session a signal
+// generated at the given time is traded in. After the regular close it is the next day.
+func tradingSessionDate(symbol models.Symbol, at time.Time) string {
+	local := at.In(exchangeLocation(symbol))
+	if exchange, exists := LookupExchange(symbol.Exchange); exists && local.Format("15:04") >= exchange.CloseTime {
+		local = local.AddDate(0, 0, 1)
+	}
+	return local.Format("2006-01-02")
 }
 
-// updateSignalCache updates the signal generation timestamp
-func (t *TradingSignalService) updateSignalCache(symbol string) {
-	t.cacheMutex.Lock()
-	defer t.cacheMutex.Unlock()
-	t.signalCache[symbol] = time.Now()
+// lastCloseBefore returns the close of the latest candle dated before the given session date
+func lastCloseBefore(data []models.OHLCData, location *time.Location, session string) float64 {
+	for i := len(data) - 1; i >= 0; i-- {
+		if data[i].Timestamp.In(location).Format("2006-01-02") < session {
+			return data[i].Close
+		}
+	}
+	return 0
 }
 
-// Close closes the service and its dependencies
+// resolveCandleSettings picks the candle interval and range for a symbol: explicit values first,
+// then per-symbol configuration, then the global defaults
+func (t *TradingSignalService) resolveCandleSettings(symbol models.Symbol, interval, dataRange string) (string, string, error) {
+	if interval == "" {
+		interval = symbolSetting(t.config.SymbolCandleIntervals, symbol)
+	}
+	if interval == "" {
+		interval = t.config.CandleInterval
+	}
+	if interval == "" {
+		interval = DefaultCandleInterval
+	}
+
+	if dataRange == "" {
+		dataRange = symbolSetting(t.config.SymbolCandleRanges, symbol)
+	}
+	if dataRange == "" {
+		dataRange = t.config.CandleRange
+	}
+	if dataRange == "" {
+		dataRange = DefaultCandleRange
+	}
+
+	interval = strings.ToLower(interval)
+	dataRange = strings.ToLower(dataRange)
+	if err := ValidateCandleSettings(interval, dataRange); err != nil {
+		return "", "", err
+	}
+
+	return interval, dataRange, nil
+}
+
+// DispatchSignal pushes a signal for a chat to every notifier when the alert policy allows it and
+// records the outcome on the signal: it fails only when no notifier accepted the signal. An
+// empty chat ID means the configured default chat.
+func (t *TradingSignalService) DispatchSignal(chatID string, signal *models.TradingSignal) *models.SignalDispatch {
+	if chatID == "" {
+		chatID = t.config.TelegramChatID
+	}
+
+	symbol := signalSymbol(signal)
+	dispatch := t.alertPolicy.Reserve(chatID, signal, symbol)
+	signal.Dispatch = dispatch
+	if dispatch.Status != DispatchSent {
+		log.Printf("Not sending %s signal for %s to chat %s: %s", signal.Signal, symbol.Ticker, chatID, dispatch.Reason)
+		return dispatch
+	}
+
+	var failures []string
+	for _, notifier := range t.notifiers {
+		if err := notifier.NotifySignal(chatID, signal); err != nil {
+			// Don't fail the request, the signal was generated successfully
+			log.Printf("Failed to send signal for %s via %s: %v", symbol.Ticker, notifier.Name(), err)
+			failures = append(failures, fmt.Sprintf("%s: %s", notifier.Name(), publicError(err)))
+		} else {
+			log.Printf("Signal for %s sent via %s", symbol.Ticker, notifier.Name())
+		}
+	}
+	for _, broadcaster := range t.broadcasters {
+		if err := broadcaster.BroadcastSignal([]string{chatID}, signal); err != nil {
+			log.Printf("Failed to send signal for %s via %s: %v", symbol.Ticker, broadcaster.Name(), err)
+			failures = append(failures, fmt.Sprintf("%s: %s", broadcaster.Name(), publicError(err)))
+		} else {
+			log.Printf("Signal for %s sent via %s", symbol.Ticker, broadcaster.Name())
+		}
+	}
+
+	if len(failures) == len(t.notifiers)+len(t.broadcasters) {
+		t.alertPolicy.Release(chatID, symbol)
+		dispatch.Status = DispatchFailed
+		dispatch.CooldownUntil = nil
+	}
+	dispatch.Reason = strings.Join(failures, "; ")
+	return dispatch
+}
+
+// RegisterMarketDataProvider registers an additional market data provider (e.g. a broker feed)
+// that can then be selected per symbol through configuration
+func (t *TradingSignalService) RegisterMarketDataProvider(provider MarketDataProvider) {
+	t.marketData.Register(provider)
+}
+
+// newDependency creates a guard for the named external service from the retry and circuit breaker configuration
+func newDependency(name string, config *models.Config) *Dependency {
+	retry := RetryPolicy{
+		MaxAttempts: config.RetryMaxAttempts,
+		BaseDelay:   time.Duration(config.RetryBaseDelayMs) * time.Millisecond,
+		MaxDelay:    time.Duration(config.RetryMaxDelaySecs) * time.Second,
+	}
+	breaker := NewCircuitBreaker(config.CircuitFailureThreshold, time.Duration(config.CircuitCooldownSecs)*time.Second)
+	return NewDependency(name, retry, breaker)
+}
+
+// DependencyStatuses returns the circuit breaker state of each external service
+func (t *TradingSignalService) DependencyStatuses() []models.DependencyStatus {
+	statuses := make([]models.DependencyStatus, 0, len(t.dependencies))
+	for _, dependency := range t.dependencies {
+		statuses = append(statuses, dependency.Status())
+	}
+	return statuses
+}
+
+// marketDataTimeout returns the timeout of each market data fetch, zero for none
+func (t *TradingSignalService) marketDataTimeout() time.Duration {
+	return time.Duration(t.config.MarketDataTimeoutSecs) * time.Second
+}
+
+// strategyTimeout returns the timeout of a strategy run, zero for none
+func (t *TradingSignalService) strategyTimeout() time.Duration {
+	return time.Duration(t.config.StrategyTimeoutSecs) * time.Second
+}
+
+// Detach returns a context that keeps ctx's values but not its cancellation and is cancelled
+// when the service closes, for work that outlives the request that started it
+func (t *TradingSignalService) Detach(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(t.ctx, cancel)
+	return detached, func() {
+		stop()
+		cancel()
+	}
+}
+
+// closeProviders closes the LLM providers that were created
+func closeProviders(providers []LLMProvider) {
+	for _, provider := range providers {
+		if err := provider.Close(); err != nil {
+			log.Printf("Failed to close %s provider: %v", provider.Name(), err)
+		}
+	}
+}
+
+// Close cancels running work and closes the service and its dependencies
 func (t *TradingSignalService) Close() error {
-	if t.geminiService != nil {
-		return t.geminiService.Close()
+	t.cancel()
+	t.feed.Close()
+	for _, broadcaster := range t.broadcasters {
+		if closer, ok := broadcaster.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				log.Printf("Failed to close %s notifier: %v", broadcaster.Name(), err)
+			}
+		}
+	}
+	if t.store != nil {
+		if err := t.store.Close(); err != nil {
+			log.Printf("Failed to close signal store: %v", err)
+		}
 	}
+	closeProviders(t.llmProviders)
 	return nil
 }
 
@@ -110,149 +592,289 @@ func (t *TradingSignalService) SendTradingSignalToChat(chatID string, signal *mo
 	return t.telegramService.sendMessageToChat(chatID, message)
 }
 
-// GenerateAllSignals generates signals for all configured stock symbols
-func (t *TradingSignalService) GenerateAllSignals() {
-	go func() {
-		log.Printf("Starting bulk signal analysis for %d stocks", len(t.config.StockSymbols))
-
-		var buySignals []*models.TradingSignal
-		var sellSignals []*models.TradingSignal
-		var holdSignals []*models.TradingSignal
-		var failedSignals []string
-
-		// Analyze each stock sequentially with 3-second delay
-		for i, symbol := range t.config.StockSymbols {
-			log.Printf("Analyzing stock %d/%d: %s", i+1, len(t.config.StockSymbols), symbol)
-
-			// Generate signal for current stock
-			signal, err := t.GenerateSignal(symbol)
-			if err != nil {
-				log.Printf("Failed to generate signal for %s: %v", symbol, err)
-				failedSignals = append(failedSignals, symbol)
-				continue
-			}
+// GenerateAllSignals starts a job that generates signals for all configured stock symbols in
+// the background, pushing each signal to the chat as it is generated and a summary at the end.
+// While the job policy allows no new job, the chat attaches to a running one and receives its
+// summary instead; the result reports whether a job was started. An empty chat ID means the
+// configured chat. The job keeps ctx's values but outlives it; it stops when cancelled or when
+// the service closes.
+func (t *TradingSignalService) GenerateAllSignals(ctx context.Context, chatID string) (*models.BulkSignalResult, bool) {
+	return t.startBulkRun(ctx, chatID, BulkModeSignals)
+}
 
-			// Categorize signal
-			switch strings.ToUpper(signal.Signal) {
-			case "BUY":
-				buySignals = append(buySignals, signal)
-			case "SELL":
-				sellSignals = append(sellSignals, signal)
-			case "WAIT", "HOLD":
-				holdSignals = append(holdSignals, signal)
-			default:
-				holdSignals = append(holdSignals, signal)
-			}
+// GenerateAllSignalsSummary is GenerateAllSignals that only sends the summary to Telegram
+func (t *TradingSignalService) GenerateAllSignalsSummary(ctx context.Context, chatID string) (*models.BulkSignalResult, bool) {
+	return t.startBulkRun(ctx, chatID, BulkModeSummary)
+}
 
-			// Wait 3 seconds before next analysis (except for the last one)
-			if i < len(t.config.StockSymbols)-1 {
-				time.Sleep(3 * time.Second)
-			}
-		}
+// GetJob returns a bulk analysis job by ID
+func (t *TradingSignalService) GetJob(id string) (*models.BulkSignalResult, error) {
+	return t.jobs.Get(id)
+}
 
-		// Create summary
-		summary := &models.SignalSummary{
-			TotalAnalyzed: len(t.config.StockSymbols),
-			BuySignals:    buySignals,
-			SellSignals:   sellSignals,
-			HoldSignals:   holdSignals,
-			FailedSignals: failedSignals,
-			GeneratedAt:   time.Now(),
-		}
+// ListJobs returns the tracked bulk analysis jobs, newest first
+func (t *TradingSignalService) ListJobs() []*models.BulkSignalResult {
+	return t.jobs.List()
+}
 
-		// Send summary to Telegram
-		if err := t.telegramService.SendSignalSummary(summary); err != nil {
-			log.Printf("Failed to send signal summary to Telegram: %v", err)
-		} else {
-			log.Printf("Signal summary sent to Telegram")
-		}
+// SubscribeJob returns the current state of a bulk analysis job and a channel of its later
+// events, closed when the job finishes; unsubscribe releases the channel
+func (t *TradingSignalService) SubscribeJob(id string) (*models.BulkSignalResult, <-chan models.JobEvent, func(), error) {
+	return t.jobs.Subscribe(id)
+}
 
-		log.Printf("Bulk signal analysis completed. Total: %d, Buy: %d, Sell: %d, Hold: %d, Failed: %d",
-			summary.TotalAnalyzed, len(buySignals), len(sellSignals), len(holdSignals), len(failedSignals))
-	}()
+// SubscribeSignals subscribes to the live feed of generated signals matching a normalized filter,
+// replaying the recent signals after lastSeq when it is positive
+func (t *TradingSignalService) SubscribeSignals(filter models.SignalFilter, lastSeq uint64) *SignalSubscription {
+	return t.feed.Subscribe(filter, lastSeq)
 }
 
-// GenerateAllSignalsSummary generates signals for all configured stock symbols but only sends summary to Telegram
-func (t *TradingSignalService) GenerateAllSignalsSummary() {
+// NormalizeSignalFilter checks a signal feed filter, resolving its symbols to tickers
+func (t *TradingSignalService) NormalizeSignalFilter(filter models.SignalFilter) (models.SignalFilter, error) {
+	return normalizeSignalFilter(t.symbols, filter)
+}
+
+// CancelJob cancels a running bulk analysis job
+func (t *TradingSignalService) CancelJob(id string) error {
+	return t.jobs.Cancel(id)
+}
+
+// CancelBulkRuns cancels every running bulk analysis job and returns how many were cancelled
+func (t *TradingSignalService) CancelBulkRuns() int {
+	return t.jobs.CancelAll()
+}
+
+// CancelChatBulkRuns detaches the chat from the running bulk analysis jobs it shares with other
+// chats and cancels those it alone is attached to
+func (t *TradingSignalService) CancelChatBulkRuns(chatID string) (cancelled, detached int) {
+	return t.jobs.CancelForChat(chatID)
+}
+
+// startBulkRun starts a bulk analysis job for the chat in the background, or attaches the chat
+// to a running job when the job policy allows no new one
+func (t *TradingSignalService) startBulkRun(ctx context.Context, chatID, mode string) (*models.BulkSignalResult, bool) {
+	if chatID == "" {
+		chatID = t.config.TelegramChatID
+	}
+
+	runCtx, cancel := t.Detach(ctx)
+	job, started := t.jobs.Acquire(chatID, mode, t.config.StockSymbols, t.jobPolicy(), cancel)
+	if !started {
+		cancel()
+		log.Printf("Chat %s attached to running bulk signal analysis %s", chatID, job.ID())
+		return job.Status(), false
+	}
+
 	go func() {
-		log.Printf("Starting bulk signal analysis for %d stocks (summary only)", len(t.config.StockSymbols))
+		defer cancel()
+		job.started()
 
-		// Send initial "request received" message
-		if err := t.telegramService.SendRequestReceivedMessage(len(t.config.StockSymbols)); err != nil {
-			log.Printf("Failed to send request received message to Telegram: %v", err)
+		dispatchChat := ""
+		if mode == BulkModeSignals {
+			dispatchChat = chatID
+			log.Printf("Starting bulk signal analysis %s for %d stocks", job.ID(), len(t.config.StockSymbols))
 		} else {
-			log.Printf("Request received message sent to Telegram")
-		}
+			log.Printf("Starting bulk signal analysis %s for %d stocks (summary only)", job.ID(), len(t.config.StockSymbols))
 
-		var buySignals []*models.TradingSignal
-		var sellSignals []*models.TradingSignal
-		var holdSignals []*models.TradingSignal
-		var failedSignals []string
-
-		// Analyze each stock sequentially with 3-second delay
-		for i, symbol := range t.config.StockSymbols {
-			log.Printf("Analyzing stock %d/%d: %s", i+1, len(t.config.StockSymbols), symbol)
-
-			// Fetch OHLC data
-			ohlcData, err := t.yahooService.FetchOHLCData(symbol)
-			if err != nil {
-				log.Printf("Failed to fetch OHLC data for %s: %v", symbol, err)
-				failedSignals = append(failedSignals, symbol)
-				continue
+			// Send initial "request received" message
+			if err := t.telegramService.SendRequestReceivedMessage(len(t.config.StockSymbols), t.bulkConcurrency()); err != nil {
+				log.Printf("Failed to send request received message to Telegram: %v", err)
+			} else {
+				log.Printf("Request received message sent to Telegram")
 			}
+		}
 
-			if len(ohlcData) == 0 {
-				log.Printf("No OHLC data available for %s", symbol)
-				failedSignals = append(failedSignals, symbol)
-				continue
-			}
+		t.completeBulkRun(job, t.analyzeAll(runCtx, job, dispatchChat))
+	}()
+	return job.Status(), true
+}
 
-			// Generate AI signal
-			signal, err := t.geminiService.GenerateTradingSignal(symbol, ohlcData)
-			if err != nil {
-				log.Printf("Failed to generate AI signal for %s: %v", symbol, err)
-				failedSignals = append(failedSignals, symbol)
-				continue
-			}
+// jobPolicy returns the limits on concurrently running bulk analysis jobs
+func (t *TradingSignalService) jobPolicy() JobPolicy {
+	return JobPolicy{
+		MaxRunning: t.config.BulkMaxRunningJobs,
+		MaxPerChat: t.config.BulkMaxJobsPerChat,
+	}
+}
 
-			// Categorize signal
-			switch strings.ToUpper(signal.Signal) {
-			case "BUY":
-				buySignals = append(buySignals, signal)
-			case "SELL":
-				sellSignals = append(sellSignals, signal)
-			case "WAIT", "HOLD":
-				holdSignals = append(holdSignals, signal)
-			default:
-				holdSignals = append(holdSignals, signal)
-			}
+// bulkRunResult is the outcome of analyzing the configured stocks
+type bulkRunResult struct {
+	summary *models.SignalSummary
+	err     error // Set when the run was cancelled before every stock was analyzed
+}
 
-			// Wait 3 seconds before next analysis (except for the last one)
-			if i < len(t.config.StockSymbols)-1 {
-				time.Sleep(3 * time.Second)
+// analyzeAll generates a signal for every configured stock with a bounded pool of workers,
+// pushing each one to dispatchChat as it is generated unless it is empty, and stops early when
+// ctx is cancelled.
+// Progress is recorded on the job, which may be nil. The
+// summary lists signals in the configured stock order whatever order they finished in.
+func (t *TradingSignalService) analyzeAll(ctx context.Context, job *BulkJob, dispatchChat string) bulkRunResult {
+	symbols := t.config.StockSymbols
+	signals := make([]*models.TradingSignal, len(symbols))
+	analyzed := make([]bool, len(symbols))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(t.bulkConcurrency(), len(symbols)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				symbol := symbols[i]
+				log.Printf("Analyzing stock %d/%d: %s", i+1, len(symbols), symbol)
+				job.symbolStarted(i)
+
+				stageCtx := withStageObserver(ctx, func(stage string) { job.symbolStage(i, stage) })
+				signal, err := t.GenerateSignal(stageCtx, symbol, "", "")
+				if err != nil && ctx.Err() != nil {
+					continue
+				}
+				job.symbolFinished(i, signal, err)
+				if err != nil {
+					log.Printf("Failed to generate signal for %s: %v", symbol, err)
+				} else if dispatchChat != "" && job.attached(dispatchChat) {
+					// Push the signal as it is generated, subject to the alert policy
+					t.DispatchSignal(dispatchChat, signal)
+				}
+				signals[i], analyzed[i] = signal, true
 			}
+		}()
+	}
+
+feed:
+	for i := range symbols {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
 		}
+	}
+	close(indexes)
+	wg.Wait()
 
-		// Create summary
-		summary := &models.SignalSummary{
-			TotalAnalyzed: len(t.config.StockSymbols),
-			BuySignals:    buySignals,
-			SellSignals:   sellSignals,
-			HoldSignals:   holdSignals,
-			FailedSignals: failedSignals,
-			GeneratedAt:   time.Now(),
+	summary := &models.SignalSummary{}
+	for i, symbol := range symbols {
+		if !analyzed[i] {
+			continue
 		}
+		summary.TotalAnalyzed++
+
+		// Categorize signal
+		signal := signals[i]
+		switch {
+		case signal == nil:
+			summary.FailedSignals = append(summary.FailedSignals, symbol)
+		case strings.ToUpper(signal.Signal) == "BUY":
+			summary.BuySignals = append(summary.BuySignals, signal)
+		case strings.ToUpper(signal.Signal) == "SELL":
+			summary.SellSignals = append(summary.SellSignals, signal)
+		default:
+			summary.HoldSignals = append(summary.HoldSignals, signal)
+		}
+	}
+
+	summary.GeneratedAt = time.Now()
+	if ctx.Err() != nil {
+		return bulkRunResult{summary: summary, err: ctx.Err()}
+	}
+	return bulkRunResult{summary: summary}
+}
+
+// bulkConcurrency returns the number of stocks a bulk run analyzes in parallel
+func (t *TradingSignalService) bulkConcurrency() int {
+	return max(1, t.config.BulkConcurrency)
+}
+
+// completeBulkRun finishes the job, then records the summary of a completed run and sends it
+// to every chat attached to the job, or tells them the run was cancelled
+func (t *TradingSignalService) completeBulkRun(job *BulkJob, result bulkRunResult) {
+	summary := result.summary
+	total := len(t.config.StockSymbols)
+	job.finish(summary, result.err)
+	status := job.Status()
+
+	if result.err != nil {
+		log.Printf("Bulk signal analysis %s cancelled after %d/%d stocks: %v", status.JobID, summary.TotalAnalyzed, total, result.err)
+		for _, chatID := range status.ChatIDs {
+			if err := t.telegramService.SendBulkCancelledMessage(chatID, summary.TotalAnalyzed, total); err != nil {
+				log.Printf("Failed to send bulk cancellation message to chat %s: %v", chatID, err)
+			}
+		}
+		return
+	}
 
-		// Send summary to Telegram
-		if err := t.telegramService.SendSignalSummary(summary); err != nil {
-			log.Printf("Failed to send signal summary to Telegram: %v", err)
+	t.recordSummary(status.Mode, summary)
+
+	// Send the summary to every chat notifier for each attached chat, and once to each broadcaster
+	for _, chatID := range status.ChatIDs {
+		for _, notifier := range t.notifiers {
+			if err := notifier.NotifySummary(chatID, summary); err != nil {
+				log.Printf("Failed to send signal summary for chat %s via %s: %v", chatID, notifier.Name(), err)
+			} else {
+				log.Printf("Signal summary for chat %s sent via %s", chatID, notifier.Name())
+			}
+		}
+	}
+	for _, broadcaster := range t.broadcasters {
+		if err := broadcaster.BroadcastSummary(status.ChatIDs, summary); err != nil {
+			log.Printf("Failed to send signal summary via %s: %v", broadcaster.Name(), err)
 		} else {
-			log.Printf("Signal summary sent to Telegram")
+			log.Printf("Signal summary for chats %s sent via %s", strings.Join(status.ChatIDs, ", "), broadcaster.Name())
 		}
+	}
 
-		log.Printf("Bulk signal analysis completed. Total: %d, Buy: %d, Sell: %d, Hold: %d, Failed: %d",
-			summary.TotalAnalyzed, len(buySignals), len(sellSignals), len(holdSignals), len(failedSignals))
-	}()
+	log.Printf("Bulk signal analysis completed. Total: %d, Buy: %d, Sell: %d, Hold: %d, Failed: %d",
+		summary.TotalAnalyzed, len(summary.BuySignals), len(summary.SellSignals), len(summary.HoldSignals), len(summary.FailedSignals))
+}
+
+// QuerySignals returns stored signals matching the query, newest first
+func (t *TradingSignalService) QuerySignals(query models.SignalQuery) ([]*models.SignalRecord, error) {
+	if t.store == nil {
+		return nil, ErrSignalHistoryDisabled
+	}
+	return t.store.QuerySignals(query)
+}
+
+// GetSignalRecord returns a stored signal by ID
+func (t *TradingSignalService) GetSignalRecord(id string) (*models.SignalRecord, error) {
+	if t.store == nil {
+		return nil, ErrSignalHistoryDisabled
+	}
+	return t.store.GetSignal(id)
+}
+
+// QuerySummaries returns stored bulk analysis summaries, newest first
+func (t *TradingSignalService) QuerySummaries(query models.SignalQuery) ([]*models.SummaryRecord, error) {
+	if t.store == nil {
+		return nil, ErrSignalHistoryDisabled
+	}
+	return t.store.QuerySummaries(query)
+}
+
+// GetSignalOutcome returns the tracked outcome of a stored signal
+func (t *TradingSignalService) GetSignalOutcome(id string) (*models.SignalOutcome, error) {
+	if t.store == nil {
+		return nil, ErrSignalHistoryDisabled
+	}
+	return t.store.GetOutcome(id)
+}
+
+// GetOutcomeStats aggregates the tracked outcomes of stored signals matching the query
+func (t *TradingSignalService) GetOutcomeStats(query models.SignalQuery) (*models.OutcomeStats, error) {
+	if t.store == nil {
+		return nil, ErrSignalHistoryDisabled
+	}
+
+	outcomes, err := QueryAllOutcomes(t.store, query)
+	if err != nil {
+		return nil, err
+	}
+	return SummarizeOutcomes(outcomes), nil
+}
+
+// NormalizeSymbol normalizes a symbol and validates it against the known-symbol list
+func (t *TradingSignalService) NormalizeSymbol(input string) (models.Symbol, error) {
+	return t.symbols.Normalize(input)
 }
 
 // GetConfiguredStocks returns the list of configured stock symbols
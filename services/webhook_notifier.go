@@ -0,0 +1,229 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+// ErrNotifierClosed is returned for deliveries requested after a notifier closed
+var ErrNotifierClosed = errors.New("notifier closed")
+
+// webhookCloseGrace is how long Close lets running deliveries finish before abandoning them
+const webhookCloseGrace = 10 * time.Second
+
+// Webhook payload events
+const (
+	WebhookEventSignal  = "signal"
+	WebhookEventSummary = "summary"
+)
+
+// WebhookEndpoint is a URL that receives webhook notifications, guarded by its own dependency
+type WebhookEndpoint struct {
+	URL        string
+	Dependency *Dependency
+}
+
+// WebhookNotifier posts signals and summaries as JSON to webhook endpoints. Each body is signed
+// with HMAC-SHA256 in the X-Signature-256 header. Deliveries run in the background with the
+// endpoint's retries; those that still fail are appended to a dead-letter log.
+type WebhookNotifier struct {
+	endpoints      []WebhookEndpoint
+	secret         []byte
+	client         *http.Client
+	deadLetterPath string
+
+	ctx        context.Context // Cancelled on Close, abandoning the remaining deliveries to the dead-letter log
+	cancel     context.CancelFunc
+	deliveries sync.WaitGroup
+	next       uint64
+	closed     bool
+	mutex      sync.Mutex // Guards next and closed, and serializes dead-letter writes
+}
+
+// NewWebhookNotifier creates a webhook notifier for http(s) endpoints, signing with secret and
+// bounding each attempt by timeout
+func NewWebhookNotifier(endpoints []WebhookEndpoint, secret string, timeout time.Duration, deadLetterPath string) (*WebhookNotifier, error) {
+	if secret == "" {
+		return nil, errors.New("a webhook secret is required to sign deliveries")
+	}
+	for _, endpoint := range endpoints {
+		parsed, err := url.Parse(endpoint.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid webhook URL %q", endpoint.URL)
+		}
+	}
+	if dir := filepath.Dir(deadLetterPath); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create webhook dead-letter directory: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WebhookNotifier{
+		endpoints:      endpoints,
+		secret:         []byte(secret),
+		client:         &http.Client{Timeout: timeout},
+		deadLetterPath: deadLetterPath,
+		ctx:            ctx,
+		cancel:         cancel,
+	}, nil
+}
+
+// Name returns the notifier name
+func (w *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+// BroadcastSignal queues a signal for delivery to every endpoint
+func (w *WebhookNotifier) BroadcastSignal(chatIDs []string, signal *models.TradingSignal) error {
+	return w.notify(&models.WebhookPayload{Event: WebhookEventSignal, ChatIDs: chatIDs, Signal: signal})
+}
+
+// BroadcastSummary queues a bulk analysis summary for delivery to every endpoint
+func (w *WebhookNotifier) BroadcastSummary(chatIDs []string, summary *models.SignalSummary) error {
+	return w.notify(&models.WebhookPayload{Event: WebhookEventSummary, ChatIDs: chatIDs, Summary: summary})
+}
+
+// Close stops accepting deliveries and lets the running ones finish for a grace period. Those
+// still being retried after it are abandoned to the dead-letter log.
+func (w *WebhookNotifier) Close() error {
+	w.mutex.Lock()
+	w.closed = true
+	w.mutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		w.deliveries.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(webhookCloseGrace)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		w.cancel()
+		<-done
+	}
+	w.cancel()
+	return nil
+}
+
+// notify stamps and encodes a payload once and delivers it to each endpoint in the background
+func (w *WebhookNotifier) notify(payload *models.WebhookPayload) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return ErrNotifierClosed
+	}
+	w.next++
+	payload.ID = fmt.Sprintf("dlv_%d_%d", time.Now().UnixNano(), w.next)
+	payload.SentAt = time.Now()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	signature := w.sign(body)
+
+	for _, endpoint := range w.endpoints {
+		w.deliveries.Add(1)
+		go func(endpoint WebhookEndpoint) {
+			defer w.deliveries.Done()
+			w.deliver(endpoint, payload, body, signature)
+		}(endpoint)
+	}
+	return nil
+}
+
+// deliver posts a payload to an endpoint with its retries, dead-lettering it if every attempt fails
+func (w *WebhookNotifier) deliver(endpoint WebhookEndpoint, payload *models.WebhookPayload, body []byte, signature string) {
+	err := endpoint.Dependency.Call(w.ctx, func(ctx context.Context) error {
+		return w.post(ctx, endpoint.URL, payload, body, signature)
+	})
+	if err == nil {
+		return
+	}
+
+	log.Printf("Failed to deliver %s webhook %s to %s: %v", payload.Event, payload.ID, endpoint.URL, err)
+	if err := w.deadLetter(models.WebhookDeadLetter{
+		URL:       endpoint.URL,
+		Event:     payload.Event,
+		Delivery:  payload.ID,
+		Signature: signature,
+		Payload:   body,
+		Error:     err.Error(),
+		FailedAt:  time.Now(),
+	}); err != nil {
+		log.Printf("Failed to write webhook %s to the dead-letter log: %v", payload.ID, err)
+	}
+}
+
+// post makes one delivery attempt; any 2xx response is a success
+func (w *WebhookNotifier) post(ctx context.Context, endpointURL string, payload *models.WebhookPayload, body []byte, signature string) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpointURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", payload.Event)
+	req.Header.Set("X-Webhook-Delivery", payload.ID)
+	req.Header.Set("X-Signature-256", signature)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return transportError(ctx, fmt.Errorf("webhook request failed: %w", err))
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return statusError(resp, fmt.Errorf("webhook returned status: %d", resp.StatusCode))
+	}
+	return nil
+}
+
+// sign returns the X-Signature-256 header value of a body: "sha256=" and the hex HMAC-SHA256
+func (w *WebhookNotifier) sign(body []byte) string {
+	mac := hmac.New(sha256.New, w.secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deadLetter appends a failed delivery to the dead-letter log as a JSON line
+func (w *WebhookNotifier) deadLetter(letter models.WebhookDeadLetter) error {
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return err
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	file, err := os.OpenFile(w.deadLetterPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/farisdewantoro/golang-day-trading-signal/models"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	var attempts atomic.Int32
+	received := make(chan models.WebhookPayload, 1)
+	accepting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(body)
+		if r.Header.Get("X-Signature-256") != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+			t.Errorf("signature %q does not match the body", r.Header.Get("X-Signature-256"))
+		}
+
+		var payload models.WebhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		if r.Header.Get("X-Webhook-Event") != payload.Event || r.Header.Get("X-Webhook-Delivery") != payload.ID {
+			t.Errorf("headers do not describe delivery %s", payload.ID)
+		}
+		received <- payload
+	}))
+	defer accepting.Close()
+
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+
+	deadLetterPath := filepath.Join(t.TempDir(), "webhooks", "dead_letters.jsonl")
+	notifier, err := NewWebhookNotifier([]WebhookEndpoint{
+		{URL: accepting.URL, Dependency: NewDependency("accepting", testRetryPolicy, NewCircuitBreaker(0, 0))},
+		{URL: rejecting.URL, Dependency: NewDependency("rejecting", testRetryPolicy, NewCircuitBreaker(0, 0))},
+	}, "secret", time.Second, deadLetterPath)
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier returned error: %v", err)
+	}
+
+	if err := notifier.BroadcastSignal([]string{"chat"}, &models.TradingSignal{StockSymbol: "BBCA", Signal: "BUY"}); err != nil {
+		t.Fatalf("BroadcastSignal returned error: %v", err)
+	}
+
+	select {
+	case payload := <-received:
+		if payload.Event != WebhookEventSignal || fmt.Sprint(payload.ChatIDs) != "[chat]" || payload.Signal.StockSymbol != "BBCA" {
+			t.Errorf("payload = %+v", payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("accepting endpoint received nothing after a retry")
+	}
+	notifier.Close()
+
+	// The rejected delivery is not retried and ends in the dead-letter log
+	file, err := os.Open(deadLetterPath)
+	if err != nil {
+		t.Fatalf("dead-letter log missing: %v", err)
+	}
+	defer file.Close()
+	var letters []models.WebhookDeadLetter
+	for scanner := bufio.NewScanner(file); scanner.Scan(); {
+		var letter models.WebhookDeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &letter); err != nil {
+			t.Fatalf("invalid dead letter: %v", err)
+		}
+		letters = append(letters, letter)
+	}
+	if len(letters) != 1 || letters[0].URL != rejecting.URL || letters[0].Signature != notifier.sign(letters[0].Payload) {
+		t.Errorf("dead letters = %+v, want the signed delivery to the rejecting endpoint", letters)
+	}
+
+	if err := notifier.BroadcastSummary([]string{"chat"}, &models.SignalSummary{}); err != ErrNotifierClosed {
+		t.Errorf("BroadcastSummary after Close returned %v", err)
+	}
+	if _, err := NewWebhookNotifier(nil, "", time.Second, deadLetterPath); err == nil {
+		t.Error("NewWebhookNotifier accepted an empty secret")
+	}
+}
+
+// recordingNotifier is a chat notifier that records the chats it was sent summaries for
+type recordingNotifier struct {
+	summaries []string
+}
+
+func (r *recordingNotifier) Name() string { return "recording" }
+
+func (r *recordingNotifier) NotifySignal(chatID string, signal *models.TradingSignal) error {
+	return nil
+}
+
+func (r *recordingNotifier) NotifySummary(chatID string, summary *models.SignalSummary) error {
+	r.summaries = append(r.summaries, chatID)
+	return nil
+}
+
+func TestCompleteBulkRunBroadcastsOnce(t *testing.T) {
+	var mutex sync.Mutex
+	var payloads []models.WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload models.WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		mutex.Lock()
+		payloads = append(payloads, payload)
+		mutex.Unlock()
+	}))
+	defer server.Close()
+
+	webhook, err := NewWebhookNotifier([]WebhookEndpoint{
+		{URL: server.URL, Dependency: NewDependency("webhook", testRetryPolicy, NewCircuitBreaker(0, 0))},
+	}, "secret", time.Second, filepath.Join(t.TempDir(), "dead_letters.jsonl"))
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier returned error: %v", err)
+	}
+	chats := &recordingNotifier{}
+	service := &TradingSignalService{
+		notifiers:    []Notifier{chats},
+		broadcasters: []BroadcastNotifier{webhook},
+		config:       &models.Config{StockSymbols: []string{"BBCA"}},
+		jobs:         NewJobManager(),
+	}
+
+	// Chat b attaches to the job chat a started
+	job := service.jobs.Start("a", BulkModeSummary, service.config.StockSymbols, func() {})
+	service.jobs.Acquire("b", BulkModeSummary, service.config.StockSymbols, JobPolicy{MaxRunning: 1, MaxPerChat: 1}, func() {})
+
+	service.completeBulkRun(job, bulkRunResult{summary: &models.SignalSummary{TotalAnalyzed: 1}})
+	webhook.Close()
+
+	if fmt.Sprint(chats.summaries) != "[a b]" {
+		t.Errorf("chat notifier sent summaries to %v, want [a b]", chats.summaries)
+	}
+	if len(payloads) != 1 || payloads[0].Event != WebhookEventSummary || fmt.Sprint(payloads[0].ChatIDs) != "[a b]" {
+		t.Errorf("webhook received %+v, want one summary for chats [a b]", payloads)
+	}
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -15,67 +16,47 @@ import (
 
 // YahooFinanceService handles fetching stock data from Yahoo Finance
 type YahooFinanceService struct {
-	client *http.Client
+	client     *http.Client
+	dependency *Dependency
 }
 
-// NewYahooFinanceService creates a new Yahoo Finance service
-func NewYahooFinanceService() *YahooFinanceService {
+// NewYahooFinanceService creates a new Yahoo Finance service whose calls are guarded by dependency
+func NewYahooFinanceService(dependency *Dependency) *YahooFinanceService {
 	return &YahooFinanceService{
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		dependency: dependency,
 	}
 }
 
-// FetchOHLCData fetches OHLC data for a given stock symbol
-func (y *YahooFinanceService) FetchOHLCData(symbol string) ([]models.OHLCData, error) {
-	baseURL := "https://query1.finance.yahoo.com/v8/finance/chart/"
-	params := url.Values{}
-	params.Add("interval", "5m")
-	params.Add("range", "2d")
-
-	url := fmt.Sprintf("%s%s?%s", baseURL, symbol+".JK", params.Encode())
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+// Name returns the provider name
+func (y *YahooFinanceService) Name() string {
+	return "yahoo"
+}
 
-	// Add headers to mimic browser request
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
-	req.Header.Set("Accept", "application/json, text/plain, */*")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
-	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Referer", "https://finance.yahoo.com/")
+// FetchOHLCData fetches OHLC data for a given Yahoo ticker (e.g. "BBCA.JK")
+func (y *YahooFinanceService) FetchOHLCData(ctx context.Context, symbol string) ([]models.OHLCData, error) {
+	return y.FetchCandles(ctx, symbol, DefaultCandleInterval, DefaultCandleRange)
+}
 
-	resp, err := y.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch data from Yahoo Finance: %w", err)
-	}
-	defer resp.Body.Close()
+// FetchCandles fetches OHLC data for a given Yahoo ticker, interval and range
+func (y *YahooFinanceService) FetchCandles(ctx context.Context, symbol, interval, dataRange string) ([]models.OHLCData, error) {
+	baseURL := "https://query1.finance.yahoo.com/v8/finance/chart/"
+	params := url.Values{}
+	params.Add("interval", interval)
+	params.Add("range", dataRange)
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Yahoo Finance API returned status: %d", resp.StatusCode)
-	}
+	url := fmt.Sprintf("%s%s?%s", baseURL, url.PathEscape(symbol), params.Encode())
 
-	body, err := io.ReadAll(resp.Body)
+	var body []byte
+	err := y.dependency.Call(ctx, func(ctx context.Context) error {
+		var err error
+		body, err = y.fetchChart(ctx, url)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Handle gzip compression
-	if resp.Header.Get("Content-Encoding") == "gzip" {
-		reader, err := gzip.NewReader(io.NopCloser(io.NewSectionReader(bytes.NewReader(body), 0, int64(len(body)))))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
-		}
-		defer reader.Close()
-
-		body, err = io.ReadAll(reader)
-		if err != nil {
-			return nil, fmt.Errorf("failed to decompress gzip response: %w", err)
-		}
+		return nil, err
 	}
 
 	var yahooResp models.YahooFinanceResponse
@@ -124,3 +105,50 @@ func (y *YahooFinanceService) FetchOHLCData(symbol string) ([]models.OHLCData, e
 
 	return ohlcData, nil
 }
+
+// fetchChart requests a chart URL and returns the decompressed response body
+func (y *YahooFinanceService) fetchChart(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Add headers to mimic browser request
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
+	req.Header.Set("Accept", "application/json, text/plain, */*")
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+	req.Header.Set("Connection", "keep-alive")
+	req.Header.Set("Referer", "https://finance.yahoo.com/")
+
+	resp, err := y.client.Do(req)
+	if err != nil {
+		return nil, transportError(ctx, fmt.Errorf("failed to fetch data from Yahoo Finance: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp, fmt.Errorf("Yahoo Finance API returned status: %d", resp.StatusCode))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, transportError(ctx, fmt.Errorf("failed to read response body: %w", err))
+	}
+
+	// Handle gzip compression
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		reader, err := gzip.NewReader(io.NopCloser(io.NewSectionReader(bytes.NewReader(body), 0, int64(len(body)))))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		defer reader.Close()
+
+		body, err = io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress gzip response: %w", err)
+		}
+	}
+
+	return body, nil
+}